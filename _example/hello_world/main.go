@@ -31,8 +31,8 @@ func main() {
 	})
 
 	// Group routes based on /api/, making sure to include the trailing slash /.
-	// This is required by the standard library syntax,
-	// which handles all requests starting with /api.
+	// Routes of the group are registered on mux with the /api prefix,
+	// so r.Pattern and r.URL.Path keep it.
 	api := mux.Group("/api/")
 
 	// use API middleware for this api group. just for testing the ability。
@@ -32,7 +32,7 @@ func BindPathParams(r *http.Request, i interface{}) error {
 	}
 
 	if err := bindData(i, params, "param", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		return bindError(err, "param")
 	}
 	return nil
 }
@@ -40,7 +40,7 @@ func BindPathParams(r *http.Request, i interface{}) error {
 // BindQueryParams binds query params to bindable object
 func BindQueryParams(r *http.Request, i interface{}) error {
 	if err := bindData(i, r.URL.Query(), "query", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		return bindError(err, "query")
 	}
 	return nil
 }
@@ -62,9 +62,12 @@ func BindBody(req *http.Request, i interface{}) (err error) {
 	switch mediatype {
 	case MIMEApplicationJSON:
 		if err = json.NewDecoder(req.Body).Decode(i); err != nil {
-			switch err.(type) {
+			switch e := err.(type) {
 			case *HTTPError:
 				return err
+			case *json.UnmarshalTypeError:
+				return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err).
+					AddFields(FieldError{Field: e.Field, Source: "body", Rule: "type", Message: err.Error()})
 			default:
 				return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 			}
@@ -84,7 +87,7 @@ func BindBody(req *http.Request, i interface{}) (err error) {
 			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if err = bindData(i, params, "form", nil); err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return bindError(err, "form")
 		}
 	case MIMEMultipartForm:
 
@@ -95,7 +98,7 @@ func BindBody(req *http.Request, i interface{}) (err error) {
 		params := req.MultipartForm
 
 		if err = bindData(i, params.Value, "form", params.File); err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return bindError(err, "form")
 		}
 	default:
 		return ErrUnsupportedMediaType
@@ -106,7 +109,7 @@ func BindBody(req *http.Request, i interface{}) (err error) {
 // BindHeaders binds HTTP headers to a bindable object
 func BindHeaders(r *http.Request, i interface{}) error {
 	if err := bindData(i, r.Header, "header", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		return bindError(err, "header")
 	}
 	return nil
 }
@@ -244,14 +247,14 @@ func bindData(destination interface{}, data map[string][]string, tag string, dat
 		// try unmarshalling first, in case we're dealing with an alias to an array type
 		if ok, err := unmarshalInputsToField(typeField.Type.Kind(), inputValue, structField); ok {
 			if err != nil {
-				return err
+				return &fieldBindError{field: inputFieldName, err: err}
 			}
 			continue
 		}
 
 		if ok, err := unmarshalInputToField(typeField.Type.Kind(), inputValue[0], structField); ok {
 			if err != nil {
-				return err
+				return &fieldBindError{field: inputFieldName, err: err}
 			}
 			continue
 		}
@@ -269,7 +272,7 @@ func bindData(destination interface{}, data map[string][]string, tag string, dat
 			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
 			for j := 0; j < numElems; j++ {
 				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
-					return err
+					return &fieldBindError{field: inputFieldName, err: err}
 				}
 			}
 			structField.Set(slice)
@@ -277,12 +280,38 @@ func bindData(destination interface{}, data map[string][]string, tag string, dat
 		}
 
 		if err := setWithProperType(structFieldKind, inputValue[0], structField); err != nil {
-			return err
+			return &fieldBindError{field: inputFieldName, err: err}
 		}
 	}
 	return nil
 }
 
+// fieldBindError is returned by bindData when a value cannot be set on a field.
+type fieldBindError struct {
+	field string
+	err   error
+}
+
+func (e *fieldBindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldBindError) Unwrap() error {
+	return e.err
+}
+
+// bindError converts a bindData error to a 400 HTTPError, with the failed field
+// described in its Fields.
+func bindError(err error, source string) *HTTPError {
+	var fe *fieldBindError
+	if !errors.As(err, &fe) {
+		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(fe.err).
+		AddFields(FieldError{Field: fe.field, Source: source, Rule: "type", Message: fe.err.Error()})
+}
+
 func setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
 	// But also call it here, in case we're dealing with an array of BindUnmarshalers
 	if ok, err := unmarshalInputToField(valueKind, val, structField); ok {
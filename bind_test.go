@@ -591,7 +591,13 @@ func TestBindUnmarshalTypeError(t *testing.T) {
 
 	err := Bind(req, u)
 
-	he := &HTTPError{StatusCode: http.StatusBadRequest, Msg: "json: cannot unmarshal string into Go struct field user.id of type int", Internal: err.(*HTTPError).Internal}
+	msg := "json: cannot unmarshal string into Go struct field user.id of type int"
+	he := &HTTPError{
+		StatusCode: http.StatusBadRequest,
+		Msg:        msg,
+		Internal:   err.(*HTTPError).Internal,
+		Fields:     []FieldError{{Field: "id", Source: "body", Rule: "type", Message: msg}},
+	}
 
 	assert.Equal(t, he, err)
 }
@@ -1509,3 +1515,50 @@ func assertMultipartFileHeader(t *testing.T, fh *multipart.FileHeader, file test
 	err = fl.Close()
 	assert.NoError(t, err)
 }
+
+func TestBindFieldErrors(t *testing.T) {
+	type params struct {
+		ID   int    `param:"id"`
+		Page int    `query:"page"`
+		Tags []int  `query:"tags"`
+		Name string `form:"name"`
+		Age  int    `form:"age"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+	req.Pattern = "GET /users/{id}"
+	req.SetPathValue("id", "abc")
+	err := BindPathParams(req, new(params))
+	if assert.IsType(t, &HTTPError{}, err) {
+		he := err.(*HTTPError)
+		assert.Equal(t, []FieldError{{Field: "id", Source: "param", Rule: "type", Message: `strconv.ParseInt: parsing "abc": invalid syntax`}}, he.Fields)
+		assert.IsType(t, &strconv.NumError{}, he.Internal)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?page=1&tags=1&tags=x", nil)
+	err = BindQueryParams(req, new(params))
+	if assert.IsType(t, &HTTPError{}, err) {
+		assert.Equal(t, "tags", err.(*HTTPError).Fields[0].Field)
+		assert.Equal(t, "query", err.(*HTTPError).Fields[0].Source)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=bob&age=old"))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	err = BindBody(req, new(params))
+	if assert.IsType(t, &HTTPError{}, err) {
+		assert.Equal(t, "age", err.(*HTTPError).Fields[0].Field)
+		assert.Equal(t, "form", err.(*HTTPError).Fields[0].Source)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"address":{"zip":"x"}}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	err = BindBody(req, &struct {
+		Address struct {
+			Zip int `json:"zip"`
+		} `json:"address"`
+	}{})
+	if assert.IsType(t, &HTTPError{}, err) {
+		assert.Equal(t, "address.zip", err.(*HTTPError).Fields[0].Field)
+		assert.Equal(t, "body", err.(*HTTPError).Fields[0].Source)
+	}
+}
@@ -0,0 +1,159 @@
+package httpz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCertReloadInterval is the interval a CertReloader polls the files at.
+const DefaultCertReloadInterval = time.Minute
+
+// CertReloader serves certificates loaded from cert and key files on disk and
+// reloads them when the files change, so certificates rotate without a restart.
+// Use its GetCertificate in a tls.Config, or set it as the Certificates of a Server.
+//
+// With several pairs, the certificate is selected by the server name sent by the
+// client (SNI), and the first pair is used when none matches.
+type CertReloader struct {
+	// Interval between polls of the modification times of the files.
+	// Default is DefaultCertReloadInterval.
+	Interval time.Duration
+	// OnError is called when a reload fails, the previous certificate is kept.
+	OnError func(err error)
+
+	mu       sync.RWMutex
+	pairs    []*certPair
+	reloadMu sync.Mutex
+}
+
+type certPair struct {
+	certFile, keyFile string
+	certMod, keyMod   time.Time
+	cert              atomic.Pointer[tls.Certificate]
+}
+
+// NewCertReloader returns a CertReloader serving the cert and key files.
+// It returns an error when the certificate cannot be loaded.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	c := new(CertReloader)
+	if err := c.Add(certFile, keyFile); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Add loads another cert and key pair, selected by SNI.
+func (c *CertReloader) Add(certFile, keyFile string) error {
+	p := &certPair{certFile: certFile, keyFile: keyFile}
+	if err := p.reload(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.pairs = append(c.pairs, p)
+	c.mu.Unlock()
+	return nil
+}
+
+// GetCertificate returns the certificate for the server name of the client hello.
+// It has the signature of tls.Config.GetCertificate.
+func (c *CertReloader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.pairs) == 0 {
+		return nil, errors.New("httpz: no certificate")
+	}
+
+	if hello != nil && hello.ServerName != "" {
+		for _, p := range c.pairs {
+			cert := p.cert.Load()
+			if cert.Leaf != nil && cert.Leaf.VerifyHostname(hello.ServerName) == nil {
+				return cert, nil
+			}
+		}
+	}
+	return c.pairs[0].cert.Load(), nil
+}
+
+// Reload reloads the pairs whose files changed since they were last loaded.
+// A pair that fails to load keeps its previous certificate, and the error is
+// passed to OnError. It returns the errors joined together.
+func (c *CertReloader) Reload() error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	c.mu.RLock()
+	pairs := c.pairs
+	c.mu.RUnlock()
+
+	var errs []error
+	for _, p := range pairs {
+		if err := p.reload(); err != nil {
+			if c.OnError != nil {
+				c.OnError(err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Watch polls the files every Interval and reloads the changed certificates
+// until ctx is done.
+func (c *CertReloader) Watch(ctx context.Context) {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultCertReloadInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Reload()
+		}
+	}
+}
+
+// reload loads the pair when either file changed since it was last loaded.
+// Calls are serialized by the reloadMu of the CertReloader, or happen before the pair is shared.
+func (p *certPair) reload() error {
+	certStat, err := os.Stat(p.certFile)
+	if err != nil {
+		return fmt.Errorf("httpz: reload certificate: %w", err)
+	}
+	keyStat, err := os.Stat(p.keyFile)
+	if err != nil {
+		return fmt.Errorf("httpz: reload certificate: %w", err)
+	}
+
+	if p.cert.Load() != nil && certStat.ModTime().Equal(p.certMod) && keyStat.ModTime().Equal(p.keyMod) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(p.certFile, p.keyFile)
+	if err != nil {
+		return fmt.Errorf("httpz: reload certificate %s: %w", p.certFile, err)
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return fmt.Errorf("httpz: reload certificate %s: %w", p.certFile, err)
+		}
+	}
+
+	p.certMod, p.keyMod = certStat.ModTime(), keyStat.ModTime()
+	p.cert.Store(&cert)
+	return nil
+}
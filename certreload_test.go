@@ -0,0 +1,144 @@
+package httpz
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCert writes a self-signed certificate for the DNS names and its key to dir.
+func writeCert(t *testing.T, dir, name string, serial int64, dnsNames ...string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	// make the change visible to the mod time poll regardless of the file system resolution
+	mod := time.Now().Add(time.Duration(serial) * time.Second)
+	assert.NoError(t, os.Chtimes(certFile, mod, mod))
+	assert.NoError(t, os.Chtimes(keyFile, mod, mod))
+	return certFile, keyFile
+}
+
+func serialOf(t *testing.T, c *CertReloader, serverName string) int64 {
+	cert, err := c.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+	assert.NoError(t, err)
+	return cert.Leaf.SerialNumber.Int64()
+}
+
+func TestCertReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir, "server", 1, "localhost")
+
+	c, err := NewCertReloader(certFile, keyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), serialOf(t, c, "localhost"))
+
+	var errs []error
+	c.OnError = func(err error) { errs = append(errs, err) }
+
+	// unchanged files are not reloaded
+	assert.NoError(t, c.Reload())
+	assert.Equal(t, int64(1), serialOf(t, c, "localhost"))
+
+	writeCert(t, dir, "server", 2, "localhost")
+	assert.NoError(t, c.Reload())
+	assert.Equal(t, int64(2), serialOf(t, c, "localhost"))
+
+	// a broken pair keeps the previous certificate
+	assert.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0o600))
+	assert.Error(t, c.Reload())
+	assert.Len(t, errs, 1)
+	assert.Equal(t, int64(2), serialOf(t, c, "localhost"))
+
+	assert.NoError(t, os.Remove(keyFile))
+	assert.ErrorIs(t, c.Reload(), os.ErrNotExist)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, int64(2), serialOf(t, c, "localhost"))
+}
+
+func TestCertReloader_SNI(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewCertReloader(writeCert(t, dir, "default", 1, "example.com"))
+	assert.NoError(t, err)
+	assert.NoError(t, c.Add(writeCert(t, dir, "api", 2, "api.example.com")))
+	assert.NoError(t, c.Add(writeCert(t, dir, "tenants", 3, "*.tenants.example.com")))
+
+	assert.Equal(t, int64(1), serialOf(t, c, "example.com"))
+	assert.Equal(t, int64(2), serialOf(t, c, "api.example.com"))
+	assert.Equal(t, int64(3), serialOf(t, c, "acme.tenants.example.com"))
+	assert.Equal(t, int64(1), serialOf(t, c, "unknown.org"))
+	assert.Equal(t, int64(1), serialOf(t, c, ""))
+
+	_, err = NewCertReloader(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key"))
+	assert.Error(t, err)
+}
+
+func TestServer_StartTLS_CertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir, "server", 1, "localhost")
+
+	c, err := NewCertReloader(certFile, keyFile)
+	assert.NoError(t, err)
+	c.Interval = 10 * time.Millisecond
+
+	s := NewServer(helloMux())
+	s.Certificates = c
+	addr, cancel, errCh := startServer(t, s, func(ctx context.Context) error {
+		return s.StartTLS(ctx, "127.0.0.1:0", nil, nil)
+	})
+
+	serial := func() int64 {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true, ServerName: "localhost"},
+			DisableKeepAlives: true,
+		}}
+		resp, err := client.Get("https://" + addr.String() + "/hello")
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "hello", string(body))
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	assert.Equal(t, int64(1), serial())
+
+	writeCert(t, dir, "server", 2, "localhost")
+	assert.Eventually(t, func() bool { return serial() == 2 }, time.Second, 20*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
@@ -8,6 +8,7 @@ package httpz
 const (
 	// MIMEApplicationJSON JavaScript Object Notation (JSON) https://www.rfc-editor.org/rfc/rfc8259
 	MIMEApplicationJSON                  = "application/json"
+	MIMEApplicationProblemJSON           = "application/problem+json" // https://www.rfc-editor.org/rfc/rfc9457
 	MIMEApplicationJavaScript            = "application/javascript"
 	MIMEApplicationJavaScriptCharsetUTF8 = MIMEApplicationJavaScript + "; " + charsetUTF8
 	MIMEApplicationXML                   = "application/xml"
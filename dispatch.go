@@ -0,0 +1,211 @@
+package httpz
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// NotFoundHandler is a HandlerFunc that returns ErrNotFound, so the response
+// is rendered by the centralized error handler.
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) error {
+	return ErrNotFound
+}
+
+// MethodNotAllowedHandler is a HandlerFunc that returns ErrMethodNotAllowed, so the response
+// is rendered by the centralized error handler.
+func MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) error {
+	return ErrMethodNotAllowed
+}
+
+// NotFound sets the handler called when no route matches the request.
+// Errors returned by h go through the centralized error handler, so
+//
+//	mux.NotFound(httpz.NotFoundHandler)
+//
+// renders 404 responses like any other *HTTPError. A group inherits the handler
+// of its parent unless it sets its own. The handler is not a route, so it is
+// not listed by Routes.
+func (sm *ServeMux) NotFound(h HandlerFunc) {
+	sm.notFound = h
+}
+
+// MethodNotAllowed sets the handler called when a route matches the request path
+// but not its method. The Allow header is set before h is called.
+// A group inherits the handler of its parent unless it sets its own.
+//
+// Without a handler the mux answers 405 like net/http does, with the Allow
+// header also listing the OPTIONS method that the mux answers automatically.
+func (sm *ServeMux) MethodNotAllowed(h HandlerFunc) {
+	sm.methodNotAllowed = h
+}
+
+// dispatch routes the request to the registered handlers. Requests no route
+// matches are served by serveUnmatched.
+func (sm *ServeMux) dispatch(w http.ResponseWriter, r *http.Request) {
+	scope := sm
+	if hg := sm.hostFor(r); hg != nil {
+		scope = hg
+	}
+
+	if sm.registry().versioned.Load() {
+		r = sm.resolveVersion(w, r, scope)
+	}
+
+	if sm.TrailingSlash != TrailingSlashStrict || sm.RedirectCase {
+		var served bool
+		if r, served = sm.fixPath(w, r, scope); served {
+			return
+		}
+	}
+
+	serveRoute(&scope.ServeMux, w, r)
+}
+
+// catchAllPattern is registered on the net/http mux of the root and of each
+// host group. Every other pattern takes precedence over it, so it only matches
+// the requests no route matches, and net/http routes the others on its own.
+const catchAllPattern = "/"
+
+// catchAllRoute is a route matching every request, such as / or /{path...},
+// registered without method and host. It is served by the catch-all pattern,
+// which would conflict with it on the net/http mux.
+type catchAllRoute struct {
+	pattern  string
+	wildcard string
+	h        http.Handler
+}
+
+// isCatchAll reports whether the net/http pattern matches every request.
+func isCatchAll(pattern string) (wildcard string, ok bool) {
+	method, host, path := parsePattern(pattern)
+	if method != "" || host != "" {
+		return "", false
+	}
+	if path == "/" {
+		return "", true
+	}
+	if name, found := strings.CutPrefix(path, "/{"); found && !strings.Contains(name, "/") {
+		name, found = strings.CutSuffix(name, "...}")
+		return name, found
+	}
+	return "", false
+}
+
+// setCatchAll stores h as the catch-all route of the root or host group.
+func (sm *ServeMux) setCatchAll(pattern, wildcard string, h http.Handler) bool {
+	return sm.catchAll.CompareAndSwap(nil, &catchAllRoute{pattern: pattern, wildcard: wildcard, h: h})
+}
+
+// lookup returns the handler and the pattern of the route of the root or host
+// group matching the request, the pattern is "" when none does. A host group
+// falls back to the routes of the root.
+func (sm *ServeMux) lookup(r *http.Request) (http.Handler, string) {
+	h, pattern := sm.ServeMux.Handler(r)
+	if pattern == catchAllPattern {
+		if c := sm.catchAll.Load(); c != nil {
+			return c.h, c.pattern
+		}
+		if sm.hostGroup == sm {
+			return sm.rootMux().lookup(r)
+		}
+		return h, ""
+	}
+	return h, pattern
+}
+
+// serveUnmatched serves the requests matching no route of the root or host
+// group. It serves the catch-all route when there is one, and a host group
+// hands the request to the routes of the root. The root answers OPTIONS
+// requests itself, and otherwise calls the not found and method not allowed
+// handlers of the deepest group matching the request.
+func (sm *ServeMux) serveUnmatched(w http.ResponseWriter, r *http.Request) {
+	if c := sm.catchAll.Load(); c != nil {
+		r.Pattern = c.pattern
+		if c.wildcard != "" {
+			r.SetPathValue(c.wildcard, strings.TrimPrefix(r.URL.Path, "/"))
+		}
+		c.h.ServeHTTP(w, r)
+		return
+	}
+	if sm.hostGroup == sm {
+		sm.rootMux().ServeMux.ServeHTTP(w, r)
+		return
+	}
+	r.Pattern = ""
+
+	scope := sm
+	if hg := sm.hostFor(r); hg != nil {
+		scope = hg
+	}
+	g := sm.groupFor(r, scope)
+	h := g.notFoundHandler()
+	if allowed := scope.allowedMethods(r); len(allowed) > 0 {
+		allowed = append(allowed, http.MethodOptions)
+		slices.Sort(allowed)
+		w.Header().Set(HeaderAllow, strings.Join(slices.Compact(allowed), ", "))
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h = g.methodNotAllowedHandler()
+		if h == nil {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+	}
+
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	g.groupChain(g.serveFunc(h)).ServeHTTP(w, r)
+}
+
+// allowedMethods returns the methods of the routes of the root or host group
+// matching the request path, the routes of the root included for a host group.
+// It probes the muxes with every registered method, like net/http does when it
+// answers with 405 Method Not Allowed.
+func (sm *ServeMux) allowedMethods(r *http.Request) []string {
+	muxes := []*http.ServeMux{&sm.ServeMux}
+	if sm.hostGroup != nil {
+		muxes = append(muxes, &sm.rootMux().ServeMux)
+	}
+
+	var allowed []string
+	probe := new(http.Request)
+	*probe = *r
+	for _, method := range sm.registry().listMethods() {
+		probe.Method = method
+		for _, mux := range muxes {
+			if _, pattern := mux.Handler(probe); pattern != "" && pattern != catchAllPattern {
+				allowed = append(allowed, method)
+				break
+			}
+		}
+	}
+
+	return allowed
+}
+
+// notFoundHandler returns the not found handler of the mux or its closest ancestor.
+func (sm *ServeMux) notFoundHandler() HandlerFunc {
+	for mux := sm; mux != nil; mux = mux.parent {
+		if mux.notFound != nil {
+			return mux.notFound
+		}
+	}
+	return nil
+}
+
+// methodNotAllowedHandler returns the method not allowed handler of the mux or its closest ancestor.
+func (sm *ServeMux) methodNotAllowedHandler() HandlerFunc {
+	for mux := sm; mux != nil; mux = mux.parent {
+		if mux.methodNotAllowed != nil {
+			return mux.methodNotAllowed
+		}
+	}
+	return nil
+}
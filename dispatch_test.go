@@ -0,0 +1,189 @@
+package httpz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMux_NotFound(t *testing.T) {
+	mux := NewServeMux()
+	mux.NotFound(NotFoundHandler)
+	mux.Get("/users", listUsers)
+
+	req := httptest.NewRequest(http.MethodGet, "/nothing", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"msg":"Not Found"}`, rec.Body.String())
+
+	// 405 keeps the net/http response when only NotFound is set
+	req = httptest.NewRequest(http.MethodPost, "/users", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS", rec.Header().Get(HeaderAllow))
+	assert.Equal(t, "Method Not Allowed\n", rec.Body.String())
+
+	// the handler is not listed as a route, however often it is set
+	mux.NotFound(NotFoundHandler)
+	routes := mux.Routes()
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "GET /users", routes[0].Pattern)
+}
+
+func TestServeMux_MethodNotAllowed(t *testing.T) {
+	mux := NewServeMux()
+	mux.MethodNotAllowed(MethodNotAllowedHandler)
+	mux.Get("/users/{id}", listUsers)
+	mux.Delete("/users/{id}", listUsers)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "DELETE, GET, HEAD, OPTIONS", rec.Header().Get(HeaderAllow))
+	assert.JSONEq(t, `{"msg":"Method Not Allowed"}`, rec.Body.String())
+
+	// 404 keeps the net/http behavior when only MethodNotAllowed is set
+	req = httptest.NewRequest(http.MethodGet, "/nothing", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "404 page not found\n", rec.Body.String())
+}
+
+func TestGroup_NotFound(t *testing.T) {
+	mux := NewServeMux()
+	mux.NotFound(NotFoundHandler)
+
+	api := mux.Group("/api/")
+	api.Get("/users", listUsers)
+
+	admin := mux.Group("/admin/")
+	admin.NotFound(func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusNotFound, "admin not found")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/nothing", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"msg":"Not Found"}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/nothing", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "admin not found", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestServeMux_AutoOptions(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/users", listUsers)
+	mux.Post("/users", listUsers)
+
+	api := mux.Group("/api/")
+	v1 := api.Group("/v1/")
+	v1.Delete("/items/{id}", listUsers)
+	v1.Patch("/items/{id}", listUsers)
+	v1.Options("/custom", func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set(HeaderAllow, "GET")
+		w.WriteHeader(http.StatusOK)
+		return nil
+	})
+	v1.Get("/custom", listUsers)
+
+	tests := []struct {
+		path  string
+		code  int
+		allow string
+	}{
+		{"/users", http.StatusNoContent, "GET, HEAD, OPTIONS, POST"},
+		{"/api/v1/items/1", http.StatusNoContent, "DELETE, OPTIONS, PATCH"},
+		{"/api/v1/custom", http.StatusOK, "GET"},
+		{"/nothing", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.allow, rec.Header().Get(HeaderAllow))
+		})
+	}
+
+	// 405 responses list the same methods without a MethodNotAllowed handler
+	req := httptest.NewRequest(http.MethodDelete, "/users", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", rec.Header().Get(HeaderAllow))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/items/1", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "DELETE, OPTIONS, PATCH", rec.Header().Get(HeaderAllow))
+}
+
+func TestServeMux_MethodNotAllowedInGroup(t *testing.T) {
+	mux := NewServeMux()
+	mux.MethodNotAllowed(MethodNotAllowedHandler)
+
+	api := mux.Group("/api/")
+	api.Post("/users", listUsers)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/users", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "OPTIONS, POST", rec.Header().Get(HeaderAllow))
+}
+
+func TestServeMux_CatchAll(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/users", listUsers)
+	mux.HandleFunc("/{path...}", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, r.Pattern+" "+r.PathValue("path"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/files/a.txt", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "/{path...} files/a.txt", rec.Body.String())
+
+	// the other routes still take precedence
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, "/{path...} users", rec.Body.String())
+
+	assert.PanicsWithValue(t, `pattern "/" conflicts with pattern "/{path...}"`, func() {
+		mux.HandleFunc("/", listUsers)
+	})
+}
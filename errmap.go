@@ -0,0 +1,98 @@
+package httpz
+
+import (
+	"errors"
+	"strconv"
+)
+
+// errorMapper converts an error to an HTTPError, it returns nil for the
+// errors it does not handle.
+type errorMapper func(err error) *HTTPError
+
+// MapError maps the errors matching target, as reported by errors.Is, to an
+// HTTPError with the status code and its status text as message. The error is
+// kept as the internal error of the HTTPError.
+//
+// Mappings apply to the errors returned by every handler of the mux tree that
+// are not a *HTTPError, after the OnError hooks saw them, and are tried in the
+// order they were added. An error no mapping matches is passed to the error
+// handler as the *HTTPError it wraps, if any, or else unchanged, and the default
+// error handlers send it as a 500 Internal Server Error. It panics if target is
+// nil or status is not a valid status code, unless errors are collected, see
+// CollectErrors.
+//
+//	mux.MapError(sql.ErrNoRows, http.StatusNotFound)
+func (sm *ServeMux) MapError(target error, status int) {
+	if target == nil {
+		sm.registry().fail("", "httpz: nil target error")
+		return
+	}
+	if status < 100 || status > 999 {
+		sm.registry().fail(target.Error(), "httpz: invalid status code "+strconv.Itoa(status))
+		return
+	}
+
+	sm.registry().addErrorMapper(func(err error) *HTTPError {
+		if !errors.Is(err, target) {
+			return nil
+		}
+		return NewHTTPError(helper(status))
+	})
+}
+
+// MapErrorAs maps the errors having an error of type T in their tree, as
+// reported by errors.As, to the HTTPError returned by fn. When fn returns nil
+// the error is left to the next mappings. The error is kept as the internal
+// error of the HTTPError unless fn sets one, see MapError.
+//
+//	httpz.MapErrorAs(mux, func(e *NotOwnerError) *httpz.HTTPError {
+//		return httpz.NewHTTPError(http.StatusForbidden, e.Error()).SetCode("not_owner")
+//	})
+func MapErrorAs[T error](sm *ServeMux, fn func(T) *HTTPError) {
+	if fn == nil {
+		sm.registry().fail("", "httpz: nil error mapping function")
+		return
+	}
+
+	sm.registry().addErrorMapper(func(err error) *HTTPError {
+		var target T
+		if !errors.As(err, &target) {
+			return nil
+		}
+		return fn(target)
+	})
+}
+
+func (rr *routeRegistry) addErrorMapper(m errorMapper) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.errMaps = append(rr.errMaps, m)
+}
+
+// mapError returns the HTTPError the error maps to. An error that is not a
+// *HTTPError is converted by the first matching mapping, or else by unwrapping
+// the *HTTPError it wraps. Other errors are returned unchanged.
+func (rr *routeRegistry) mapError(err error) error {
+	if _, ok := err.(*HTTPError); ok {
+		return err
+	}
+
+	rr.mu.RLock()
+	mappers := rr.errMaps
+	rr.mu.RUnlock()
+
+	for _, m := range mappers {
+		if he := m(err); he != nil {
+			if he.Internal == nil {
+				he = he.WithInternal(err)
+			}
+			return he
+		}
+	}
+
+	var he *HTTPError
+	if errors.As(err, &he) {
+		return he
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package httpz
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type notOwnerError struct {
+	user string
+}
+
+func (e *notOwnerError) Error() string { return e.user + " is not the owner" }
+
+func TestServeMux_MapError(t *testing.T) {
+	errConflict := errors.New("conflict")
+
+	mux := NewServeMux()
+	mux.MapError(sql.ErrNoRows, http.StatusNotFound)
+	MapErrorAs(mux, func(e *notOwnerError) *HTTPError {
+		if e.user == "" {
+			return nil
+		}
+		return NewHTTPError(http.StatusForbidden, e.Error()).SetCode("not_owner")
+	})
+	MapErrorAs(mux, func(e *notOwnerError) *HTTPError {
+		return ErrUnauthorized
+	})
+	mux.MapError(errConflict, http.StatusConflict)
+	mux.MapError(errConflict, http.StatusTeapot)
+
+	var hooked error
+	mux.OnError(func(r *http.Request, err error) { hooked = err })
+
+	errs := map[string]error{
+		"/rows":     fmt.Errorf("get user: %w", sql.ErrNoRows),
+		"/owner":    &notOwnerError{user: "bob"},
+		"/conflict": errConflict,
+		"/wrapped":  fmt.Errorf("wrapped: %w", ErrBadRequest),
+		"/other":    errors.New("boom"),
+	}
+	api := mux.Group("/api/")
+	api.Get("/{name}", func(w http.ResponseWriter, r *http.Request) error {
+		return errs["/"+r.PathValue("name")]
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+		hooked error
+	}{
+		{"/rows", http.StatusNotFound, `{"msg":"Not Found"}`, sql.ErrNoRows},
+		{"/owner", http.StatusForbidden, `{"msg":"bob is not the owner","code":"not_owner"}`, errs["/owner"]},
+		{"/conflict", http.StatusConflict, `{"msg":"Conflict"}`, errConflict},
+		{"/wrapped", http.StatusBadRequest, `{"msg":"Bad Request"}`, ErrBadRequest},
+		{"/other", http.StatusInternalServerError, `{"msg":"Internal Server Error"}`, errs["/other"]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api"+tt.path, nil))
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.JSONEq(t, tt.body, rec.Body.String())
+			assert.Equal(t, errs[tt.path], hooked)
+			assert.ErrorIs(t, hooked, tt.hooked)
+		})
+	}
+
+	// a mapping returning nil leaves the error to the next mappings
+	api.Get("/anonymous", func(w http.ResponseWriter, r *http.Request) error {
+		return &notOwnerError{}
+	})
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/anonymous", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, ErrUnauthorized.Internal, "the shared error is not modified")
+}
+
+func TestServeMux_MapErrorInvalid(t *testing.T) {
+	mux := NewServeMux()
+	assert.PanicsWithValue(t, "httpz: nil target error", func() {
+		mux.MapError(nil, http.StatusNotFound)
+	})
+	assert.PanicsWithValue(t, "httpz: invalid status code 42", func() {
+		mux.MapError(sql.ErrNoRows, 42)
+	})
+	assert.Panics(t, func() {
+		MapErrorAs[*notOwnerError](mux, nil)
+	})
+}
+
+func TestServeMux_MapErrorUnmapped(t *testing.T) {
+	buf := new(bytes.Buffer)
+	old := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(buf, nil)))
+	defer slog.SetDefault(old)
+
+	mux := NewServeMux()
+	mux.MapError(sql.ErrNoRows, http.StatusNotFound)
+
+	var handled error
+	mux.RequestErrHandlerFunc = func(err error, w http.ResponseWriter, r *http.Request) {
+		handled = err
+		DefaultRequestErrHandlerFunc(err, w, r)
+	}
+	mux.Get("/fail", func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("db down")
+	})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	// the error handler gets the error itself, the default one sends it as a 500
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"msg":"Internal Server Error"}`, rec.Body.String())
+	assert.EqualError(t, handled, "db down")
+	assert.Contains(t, buf.String(), `msg="db down"`)
+}
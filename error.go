@@ -8,28 +8,87 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
 )
 
 // ErrHandlerFunc defines the function signature for centralized error handling.
 type ErrHandlerFunc func(err error, w http.ResponseWriter)
 
+// RequestErrHandlerFunc defines the function signature for centralized error handling
+// with access to the request that produced the error.
+type RequestErrHandlerFunc func(err error, w http.ResponseWriter, r *http.Request)
+
 // DefaultErrHandlerFunc is the default centralized error handling function.
 // It only triggers an error response for *HTTPError.
 func DefaultErrHandlerFunc(err error, w http.ResponseWriter) {
 	if he, ok := err.(*HTTPError); ok {
 		rw := NewHelperRW(w)
-		rw.JSON(he.StatusCode, Map{"msg": he.Msg})
+		rw.JSON(he.StatusCode, errorBody(he))
 	} else {
 		slog.Error(err.Error())
 	}
 }
 
+// DefaultRequestErrHandlerFunc is the default request-aware error handling function.
+// It sends *HTTPError as JSON, XML, HTML or plain text depending on the Accept
+// header, see ErrorRenderer, without a body for HEAD requests. It logs any other
+// error, sent as a 500 Internal Server Error without its message, as well as
+// server errors with an internal error, together with the method, path and request ID.
+func DefaultRequestErrHandlerFunc(err error, w http.ResponseWriter, r *http.Request) {
+	defaultErrorRenderer.HandleError(err, w, r)
+}
+
+// errorBody returns the JSON body describing the HTTPError: its message, and
+// its code and field errors when set.
+func errorBody(he *HTTPError) Map {
+	body := Map{"msg": he.Msg}
+	if he.Code != "" {
+		body["code"] = he.Code
+	}
+	if len(he.Fields) > 0 {
+		body["fields"] = he.Fields
+	}
+	return body
+}
+
+// requestAttrs returns the slog attributes describing a request.
+func requestAttrs(w http.ResponseWriter, r *http.Request) []any {
+	attrs := []any{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+	}
+
+	if r.Pattern != "" {
+		attrs = append(attrs, slog.String("pattern", r.Pattern))
+	}
+
+	reqID := r.Header.Get(HeaderXRequestID)
+	if reqID == "" {
+		reqID = w.Header().Get(HeaderXRequestID)
+	}
+	if reqID != "" {
+		attrs = append(attrs, slog.String("request_id", reqID))
+	}
+
+	return attrs
+}
+
 // HTTPError represents a custom error type inspired by Echo.
 type HTTPError struct {
-	StatusCode int    // HTTP status code
-	Msg        string // Error message
-	Internal   error  // Internal error
+	StatusCode int          // HTTP status code
+	Msg        string       // Error message
+	Internal   error        // Internal error
+	Code       string       // Machine-readable application error code, optional
+	Fields     []FieldError // Fields of the request that failed, optional
+
+	// Problem details members, see ProblemErrHandlerFunc
+	Type       string         // URI identifying the problem type, about:blank if empty
+	Instance   string         // URI identifying the occurrence of the problem, optional
+	Extensions map[string]any // Extension members, optional
+
+	sentinel bool // predefined error, never modified
 }
 
 // NewHTTPError creates a new HTTPError with the given status code and message.
@@ -40,12 +99,125 @@ func NewHTTPError(statusCode int, msg string) *HTTPError {
 	}
 }
 
+// FieldError describes a field of the request that failed binding or validation.
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`                       // Path of the field, e.g. address.city
+	Source  string `json:"source,omitempty" xml:"source,omitempty"` // Where the field comes from: param, query, header, form or body
+	Rule    string `json:"rule,omitempty" xml:"rule,omitempty"`     // Rule that failed, e.g. type or required
+	Message string `json:"message" xml:"message"`                   // Human-readable description
+}
+
+// newSentinel creates a predefined HTTPError, which the Set methods copy instead of modifying.
+func newSentinel(statusCode int, msg string) *HTTPError {
+	return &HTTPError{
+		StatusCode: statusCode,
+		Msg:        msg,
+		sentinel:   true,
+	}
+}
+
+// mutable returns the HTTPError itself, or a copy when it is a predefined error.
+func (e *HTTPError) mutable() *HTTPError {
+	if e.sentinel {
+		return e.clone()
+	}
+	return e
+}
+
+// clone returns a copy of the HTTPError that is not a predefined error.
+func (e *HTTPError) clone() *HTTPError {
+	c := *e
+	c.sentinel = false
+	c.Extensions = maps.Clone(e.Extensions)
+	c.Fields = slices.Clone(e.Fields)
+	return &c
+}
+
 // SetInternal sets the internal error for the HTTPError.
+// On a predefined error such as ErrNotFound it sets it on a copy, which is returned.
 func (e *HTTPError) SetInternal(err error) *HTTPError {
+	e = e.mutable()
 	e.Internal = err
 	return e
 }
 
+// SetCode sets the application error code of the HTTPError.
+// On a predefined error it sets it on a copy, which is returned.
+func (e *HTTPError) SetCode(code string) *HTTPError {
+	e = e.mutable()
+	e.Code = code
+	return e
+}
+
+// AddFields appends field errors to the HTTPError.
+// On a predefined error it appends them to a copy, which is returned.
+//
+//	return httpz.ErrUnprocessableEntity.AddFields(httpz.FieldError{
+//		Field: "email", Source: "body", Rule: "required", Message: "email is required",
+//	})
+func (e *HTTPError) AddFields(fields ...FieldError) *HTTPError {
+	e = e.mutable()
+	e.Fields = append(e.Fields, fields...)
+	return e
+}
+
+// SetType sets the problem type URI of the HTTPError.
+// On a predefined error it sets it on a copy, which is returned.
+func (e *HTTPError) SetType(uri string) *HTTPError {
+	e = e.mutable()
+	e.Type = uri
+	return e
+}
+
+// SetInstance sets the URI identifying the occurrence of the problem.
+// On a predefined error it sets it on a copy, which is returned.
+func (e *HTTPError) SetInstance(uri string) *HTTPError {
+	e = e.mutable()
+	e.Instance = uri
+	return e
+}
+
+// SetExtension sets an extension member of the problem details.
+// On a predefined error it sets it on a copy, which is returned.
+func (e *HTTPError) SetExtension(key string, value any) *HTTPError {
+	e = e.mutable()
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]any)
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// WithInternal returns a copy of the HTTPError with the internal error set.
+//
+//	return httpz.ErrNotFound.WithInternal(err)
+func (e *HTTPError) WithInternal(err error) *HTTPError {
+	c := e.clone()
+	c.Internal = err
+	return c
+}
+
+// WithMessage returns a copy of the HTTPError with the message set.
+func (e *HTTPError) WithMessage(msg string) *HTTPError {
+	c := e.clone()
+	c.Msg = msg
+	return c
+}
+
+// WithStatus returns a copy of the HTTPError with the status code set.
+func (e *HTTPError) WithStatus(statusCode int) *HTTPError {
+	c := e.clone()
+	c.StatusCode = statusCode
+	return c
+}
+
+// Is reports whether target is a predefined error with the same status code,
+// so errors.Is(err, httpz.ErrNotFound) matches any 404 HTTPError.
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	return ok && t.sentinel && t.StatusCode == e.StatusCode
+}
+
 // Error returns the error message for the HTTPError.
 func (e *HTTPError) Error() string {
 	if e.Internal == nil {
@@ -64,48 +236,49 @@ func helper(code int) (int, string) {
 	return code, http.StatusText(code)
 }
 
-// Predefined HTTP errors
+// Predefined HTTP errors. They are never modified, derive errors from them
+// with WithInternal, WithMessage and WithStatus.
 var (
-	ErrBadRequest                    = NewHTTPError(helper(http.StatusBadRequest))                    // HTTP 400 Bad Request
-	ErrUnauthorized                  = NewHTTPError(helper(http.StatusUnauthorized))                  // HTTP 401 Unauthorized
-	ErrPaymentRequired               = NewHTTPError(helper(http.StatusPaymentRequired))               // HTTP 402 Payment Required
-	ErrForbidden                     = NewHTTPError(helper(http.StatusForbidden))                     // HTTP 403 Forbidden
-	ErrNotFound                      = NewHTTPError(helper(http.StatusNotFound))                      // HTTP 404 Not Found
-	ErrMethodNotAllowed              = NewHTTPError(helper(http.StatusMethodNotAllowed))              // HTTP 405 Method Not Allowed
-	ErrNotAcceptable                 = NewHTTPError(helper(http.StatusNotAcceptable))                 // HTTP 406 Not Acceptable
-	ErrProxyAuthRequired             = NewHTTPError(helper(http.StatusProxyAuthRequired))             // HTTP 407 Proxy AuthRequired
-	ErrRequestTimeout                = NewHTTPError(helper(http.StatusRequestTimeout))                // HTTP 408 Request Timeout
-	ErrConflict                      = NewHTTPError(helper(http.StatusConflict))                      // HTTP 409 Conflict
-	ErrGone                          = NewHTTPError(helper(http.StatusGone))                          // HTTP 410 Gone
-	ErrLengthRequired                = NewHTTPError(helper(http.StatusLengthRequired))                // HTTP 411 Length Required
-	ErrPreconditionFailed            = NewHTTPError(helper(http.StatusPreconditionFailed))            // HTTP 412 Precondition Failed
-	ErrStatusRequestEntityTooLarge   = NewHTTPError(helper(http.StatusRequestEntityTooLarge))         // HTTP 413 Payload Too Large
-	ErrRequestURITooLong             = NewHTTPError(helper(http.StatusRequestURITooLong))             // HTTP 414 URI Too Long
-	ErrUnsupportedMediaType          = NewHTTPError(helper(http.StatusUnsupportedMediaType))          // HTTP 415 Unsupported Media Type
-	ErrRequestedRangeNotSatisfiable  = NewHTTPError(helper(http.StatusRequestedRangeNotSatisfiable))  // HTTP 416 Range Not Satisfiable
-	ErrExpectationFailed             = NewHTTPError(helper(http.StatusExpectationFailed))             // HTTP 417 Expectation Failed
-	ErrTeapot                        = NewHTTPError(helper(http.StatusTeapot))                        // HTTP 418 I'm a teapot
-	ErrMisdirectedRequest            = NewHTTPError(helper(http.StatusMisdirectedRequest))            // HTTP 421 Misdirected Request
-	ErrUnprocessableEntity           = NewHTTPError(helper(http.StatusUnprocessableEntity))           // HTTP 422 Unprocessable Entity
-	ErrLocked                        = NewHTTPError(helper(http.StatusLocked))                        // HTTP 423 Locked
-	ErrFailedDependency              = NewHTTPError(helper(http.StatusFailedDependency))              // HTTP 424 Failed Dependency
-	ErrTooEarly                      = NewHTTPError(helper(http.StatusTooEarly))                      // HTTP 425 Too Early
-	ErrUpgradeRequired               = NewHTTPError(helper(http.StatusUpgradeRequired))               // HTTP 426 Upgrade Required
-	ErrPreconditionRequired          = NewHTTPError(helper(http.StatusPreconditionRequired))          // HTTP 428 Precondition Required
-	ErrTooManyRequests               = NewHTTPError(helper(http.StatusTooManyRequests))               // HTTP 429 Too Many Requests
-	ErrRequestHeaderFieldsTooLarge   = NewHTTPError(helper(http.StatusRequestHeaderFieldsTooLarge))   // HTTP 431 Request Header Fields Too Large
-	ErrUnavailableForLegalReasons    = NewHTTPError(helper(http.StatusUnavailableForLegalReasons))    // HTTP 451 Unavailable For Legal Reasons
-	ErrInternalServerError           = NewHTTPError(helper(http.StatusInternalServerError))           // HTTP 500 Internal Server Error
-	ErrNotImplemented                = NewHTTPError(helper(http.StatusNotImplemented))                // HTTP 501 Not Implemented
-	ErrBadGateway                    = NewHTTPError(helper(http.StatusBadGateway))                    // HTTP 502 Bad Gateway
-	ErrServiceUnavailable            = NewHTTPError(helper(http.StatusServiceUnavailable))            // HTTP 503 Service Unavailable
-	ErrGatewayTimeout                = NewHTTPError(helper(http.StatusGatewayTimeout))                // HTTP 504 Gateway Timeout
-	ErrHTTPVersionNotSupported       = NewHTTPError(helper(http.StatusHTTPVersionNotSupported))       // HTTP 505 HTTP Version Not Supported
-	ErrVariantAlsoNegotiates         = NewHTTPError(helper(http.StatusVariantAlsoNegotiates))         // HTTP 506 Variant Also Negotiates
-	ErrInsufficientStorage           = NewHTTPError(helper(http.StatusInsufficientStorage))           // HTTP 507 Insufficient Storage
-	ErrLoopDetected                  = NewHTTPError(helper(http.StatusLoopDetected))                  // HTTP 508 Loop Detected
-	ErrNotExtended                   = NewHTTPError(helper(http.StatusNotExtended))                   // HTTP 510 Not Extended
-	ErrNetworkAuthenticationRequired = NewHTTPError(helper(http.StatusNetworkAuthenticationRequired)) // HTTP 511 Network Authentication Required
+	ErrBadRequest                    = newSentinel(helper(http.StatusBadRequest))                    // HTTP 400 Bad Request
+	ErrUnauthorized                  = newSentinel(helper(http.StatusUnauthorized))                  // HTTP 401 Unauthorized
+	ErrPaymentRequired               = newSentinel(helper(http.StatusPaymentRequired))               // HTTP 402 Payment Required
+	ErrForbidden                     = newSentinel(helper(http.StatusForbidden))                     // HTTP 403 Forbidden
+	ErrNotFound                      = newSentinel(helper(http.StatusNotFound))                      // HTTP 404 Not Found
+	ErrMethodNotAllowed              = newSentinel(helper(http.StatusMethodNotAllowed))              // HTTP 405 Method Not Allowed
+	ErrNotAcceptable                 = newSentinel(helper(http.StatusNotAcceptable))                 // HTTP 406 Not Acceptable
+	ErrProxyAuthRequired             = newSentinel(helper(http.StatusProxyAuthRequired))             // HTTP 407 Proxy AuthRequired
+	ErrRequestTimeout                = newSentinel(helper(http.StatusRequestTimeout))                // HTTP 408 Request Timeout
+	ErrConflict                      = newSentinel(helper(http.StatusConflict))                      // HTTP 409 Conflict
+	ErrGone                          = newSentinel(helper(http.StatusGone))                          // HTTP 410 Gone
+	ErrLengthRequired                = newSentinel(helper(http.StatusLengthRequired))                // HTTP 411 Length Required
+	ErrPreconditionFailed            = newSentinel(helper(http.StatusPreconditionFailed))            // HTTP 412 Precondition Failed
+	ErrStatusRequestEntityTooLarge   = newSentinel(helper(http.StatusRequestEntityTooLarge))         // HTTP 413 Payload Too Large
+	ErrRequestURITooLong             = newSentinel(helper(http.StatusRequestURITooLong))             // HTTP 414 URI Too Long
+	ErrUnsupportedMediaType          = newSentinel(helper(http.StatusUnsupportedMediaType))          // HTTP 415 Unsupported Media Type
+	ErrRequestedRangeNotSatisfiable  = newSentinel(helper(http.StatusRequestedRangeNotSatisfiable))  // HTTP 416 Range Not Satisfiable
+	ErrExpectationFailed             = newSentinel(helper(http.StatusExpectationFailed))             // HTTP 417 Expectation Failed
+	ErrTeapot                        = newSentinel(helper(http.StatusTeapot))                        // HTTP 418 I'm a teapot
+	ErrMisdirectedRequest            = newSentinel(helper(http.StatusMisdirectedRequest))            // HTTP 421 Misdirected Request
+	ErrUnprocessableEntity           = newSentinel(helper(http.StatusUnprocessableEntity))           // HTTP 422 Unprocessable Entity
+	ErrLocked                        = newSentinel(helper(http.StatusLocked))                        // HTTP 423 Locked
+	ErrFailedDependency              = newSentinel(helper(http.StatusFailedDependency))              // HTTP 424 Failed Dependency
+	ErrTooEarly                      = newSentinel(helper(http.StatusTooEarly))                      // HTTP 425 Too Early
+	ErrUpgradeRequired               = newSentinel(helper(http.StatusUpgradeRequired))               // HTTP 426 Upgrade Required
+	ErrPreconditionRequired          = newSentinel(helper(http.StatusPreconditionRequired))          // HTTP 428 Precondition Required
+	ErrTooManyRequests               = newSentinel(helper(http.StatusTooManyRequests))               // HTTP 429 Too Many Requests
+	ErrRequestHeaderFieldsTooLarge   = newSentinel(helper(http.StatusRequestHeaderFieldsTooLarge))   // HTTP 431 Request Header Fields Too Large
+	ErrUnavailableForLegalReasons    = newSentinel(helper(http.StatusUnavailableForLegalReasons))    // HTTP 451 Unavailable For Legal Reasons
+	ErrInternalServerError           = newSentinel(helper(http.StatusInternalServerError))           // HTTP 500 Internal Server Error
+	ErrNotImplemented                = newSentinel(helper(http.StatusNotImplemented))                // HTTP 501 Not Implemented
+	ErrBadGateway                    = newSentinel(helper(http.StatusBadGateway))                    // HTTP 502 Bad Gateway
+	ErrServiceUnavailable            = newSentinel(helper(http.StatusServiceUnavailable))            // HTTP 503 Service Unavailable
+	ErrGatewayTimeout                = newSentinel(helper(http.StatusGatewayTimeout))                // HTTP 504 Gateway Timeout
+	ErrHTTPVersionNotSupported       = newSentinel(helper(http.StatusHTTPVersionNotSupported))       // HTTP 505 HTTP Version Not Supported
+	ErrVariantAlsoNegotiates         = newSentinel(helper(http.StatusVariantAlsoNegotiates))         // HTTP 506 Variant Also Negotiates
+	ErrInsufficientStorage           = newSentinel(helper(http.StatusInsufficientStorage))           // HTTP 507 Insufficient Storage
+	ErrLoopDetected                  = newSentinel(helper(http.StatusLoopDetected))                  // HTTP 508 Loop Detected
+	ErrNotExtended                   = newSentinel(helper(http.StatusNotExtended))                   // HTTP 510 Not Extended
+	ErrNetworkAuthenticationRequired = newSentinel(helper(http.StatusNetworkAuthenticationRequired)) // HTTP 511 Network Authentication Required
 
 	ErrValidatorNotRegistered = errors.New("validator not registered")
 	ErrRendererNotRegistered  = errors.New("renderer not registered")
@@ -113,4 +286,5 @@ var (
 	ErrCookieNotFound         = errors.New("cookie not found")
 	ErrInvalidCertOrKeyType   = errors.New("invalid cert or key type, must be string or []byte")
 	ErrInvalidListenerNetwork = errors.New("invalid listener network")
+	ErrRouteNameNotFound      = errors.New("route name not found")
 )
@@ -0,0 +1,126 @@
+package httpz
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html/template"
+	"log/slog"
+	"net/http"
+	texttemplate "text/template"
+)
+
+// ErrorRenderer renders *HTTPError responses in the format preferred by the
+// Accept header of the request: JSON, XML, HTML or plain text. JSON is used
+// when the header is missing or accepts any format.
+// Each format can be customized, the zero value renders
+//
+//	{"msg":"Not Found"}, with code and fields members when set
+//	<error><msg>Not Found</msg></error>, with code and fields elements when set
+//	<h1>404 Not Found</h1> in a minimal page
+//	Not Found
+type ErrorRenderer struct {
+	// JSON returns the value encoded as the JSON body.
+	JSON func(he *HTTPError) any
+	// XML returns the value encoded as the XML body.
+	XML func(he *HTTPError) any
+	// HTML is executed with the *HTTPError to render the HTML body.
+	HTML *template.Template
+	// Text is executed with the *HTTPError to render the plain text body.
+	Text *texttemplate.Template
+}
+
+var (
+	defaultErrorHTML = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.StatusCode}} {{.Msg}}</title></head>
+<body><h1>{{.StatusCode}} {{.Msg}}</h1></body>
+</html>
+`))
+	defaultErrorText = texttemplate.Must(texttemplate.New("error").Parse(`{{.Msg}}`))
+
+	defaultErrorRenderer = new(ErrorRenderer)
+)
+
+type xmlError struct {
+	XMLName xml.Name   `xml:"error"`
+	Msg     string     `xml:"msg"`
+	Code    string     `xml:"code,omitempty"`
+	Fields  *xmlFields `xml:"fields,omitempty"`
+}
+
+type xmlFields struct {
+	Field []FieldError `xml:"field"`
+}
+
+// Render sends the HTTPError in the format preferred by the request.
+// When a template fails, the message is sent as plain text and the error returned.
+func (er *ErrorRenderer) Render(w http.ResponseWriter, r *http.Request, he *HTTPError) error {
+	rw := NewHelperRW(w)
+
+	switch negotiate(r, MIMEApplicationJSON, MIMEApplicationXML, MIMETextHTML, MIMETextPlain) {
+	case MIMEApplicationXML:
+		xe := xmlError{Msg: he.Msg, Code: he.Code}
+		if len(he.Fields) > 0 {
+			xe.Fields = &xmlFields{Field: he.Fields}
+		}
+		var body any = xe
+		if er.XML != nil {
+			body = er.XML(he)
+		}
+		return rw.XML(he.StatusCode, body, "")
+
+	case MIMETextHTML:
+		tmpl := er.HTML
+		if tmpl == nil {
+			tmpl = defaultErrorHTML
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, he); err != nil {
+			rw.String(he.StatusCode, he.Msg)
+			return err
+		}
+		return rw.HTML(he.StatusCode, buf.String())
+
+	case MIMETextPlain:
+		tmpl := er.Text
+		if tmpl == nil {
+			tmpl = defaultErrorText
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, he); err != nil {
+			rw.String(he.StatusCode, he.Msg)
+			return err
+		}
+		return rw.String(he.StatusCode, buf.String())
+
+	default:
+		var body any = errorBody(he)
+		if er.JSON != nil {
+			body = er.JSON(he)
+		}
+		return rw.JSON(he.StatusCode, body)
+	}
+}
+
+// HandleError is a RequestErrHandlerFunc behaving like DefaultRequestErrHandlerFunc,
+// with the responses rendered by the ErrorRenderer.
+//
+//	mux.RequestErrHandlerFunc = (&httpz.ErrorRenderer{HTML: errorPage}).HandleError
+func (er *ErrorRenderer) HandleError(err error, w http.ResponseWriter, r *http.Request) {
+	he, ok := err.(*HTTPError)
+	if !ok {
+		slog.Error(err.Error(), requestAttrs(w, r)...)
+		he = ErrInternalServerError
+	} else if he.StatusCode >= http.StatusInternalServerError && he.Internal != nil {
+		slog.Error(he.Error(), requestAttrs(w, r)...)
+	}
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(he.StatusCode)
+		return
+	}
+
+	if err := er.Render(w, r, he); err != nil {
+		slog.Error(err.Error(), requestAttrs(w, r)...)
+	}
+}
@@ -0,0 +1,71 @@
+package httpz
+
+import (
+	"encoding/xml"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	texttemplate "text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRequestErrHandlerFunc_Negotiate(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", MIMEApplicationJSON, "{\"msg\":\"user not found\"}\n"},
+		{"*/*", MIMEApplicationJSON, "{\"msg\":\"user not found\"}\n"},
+		{"application/xml", MIMEApplicationXMLCharsetUTF8, xml.Header + "<error><msg>user not found</msg></error>"},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", MIMETextHTMLCharsetUTF8, "<h1>404 user not found</h1>"},
+		{"text/plain", MIMETextPlainCharsetUTF8, "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			req.Header.Set(HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			DefaultRequestErrHandlerFunc(NewHTTPError(http.StatusNotFound, "user not found"), rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get(HeaderContentType))
+			if tt.contentType == MIMETextHTMLCharsetUTF8 {
+				assert.Contains(t, rec.Body.String(), tt.body)
+			} else {
+				assert.Equal(t, tt.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestErrorRenderer_Custom(t *testing.T) {
+	er := &ErrorRenderer{
+		JSON: func(he *HTTPError) any { return Map{"error": he.Msg, "code": he.StatusCode} },
+		HTML: template.Must(template.New("").Parse(`<p class="error">{{.Msg}}</p>`)),
+		Text: texttemplate.Must(texttemplate.New("").Parse(`error {{.StatusCode}}: {{.Msg}}`)),
+	}
+
+	mux := NewServeMux()
+	mux.RequestErrHandlerFunc = er.HandleError
+	mux.Get("/fail", func(w http.ResponseWriter, r *http.Request) error {
+		return NewHTTPError(http.StatusConflict, "<taken>")
+	})
+
+	for accept, want := range map[string]string{
+		MIMEApplicationJSON: "{\"code\":409,\"error\":\"\\u003ctaken\\u003e\"}\n",
+		MIMETextHTML:        `<p class="error">&lt;taken&gt;</p>`,
+		MIMETextPlain:       "error 409: <taken>",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+		req.Header.Set(HeaderAccept, accept)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusConflict, rec.Code, accept)
+		assert.Equal(t, want, rec.Body.String(), accept)
+	}
+}
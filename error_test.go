@@ -1,9 +1,13 @@
 package httpz
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,3 +49,114 @@ func TestDefaultErrHandlerFunc(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	assert.JSONEq(t, `{"msg":"bad request"}`, rec.Body.String())
 }
+
+func TestDefaultRequestErrHandlerFunc(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	DefaultRequestErrHandlerFunc(NewHTTPError(http.StatusBadRequest, "bad request"), rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"msg":"bad request"}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodHead, "/", nil)
+	rec = httptest.NewRecorder()
+	DefaultRequestErrHandlerFunc(NewHTTPError(http.StatusNotFound, "not found"), rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestDefaultRequestErrHandlerFunc_Log(t *testing.T) {
+	buf := new(bytes.Buffer)
+	old := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(buf, nil)))
+	defer slog.SetDefault(old)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.Header.Set(HeaderXRequestID, "req-1")
+	rec := httptest.NewRecorder()
+	DefaultRequestErrHandlerFunc(errors.New("boom"), rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"msg":"Internal Server Error"}`, rec.Body.String())
+	assert.Contains(t, buf.String(), "msg=boom")
+	assert.Contains(t, buf.String(), "method=POST")
+	assert.Contains(t, buf.String(), "path=/users")
+	assert.Contains(t, buf.String(), "request_id=req-1")
+}
+
+func TestHTTPError_Sentinel(t *testing.T) {
+	internal := errors.New("db down")
+
+	err := ErrNotFound.SetInternal(internal)
+	assert.NotSame(t, ErrNotFound, err)
+	assert.Equal(t, internal, err.Internal)
+	assert.Nil(t, ErrNotFound.Internal)
+
+	err = ErrBadRequest.WithMessage("name is required").WithInternal(internal)
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t, "name is required", err.Msg)
+	assert.Equal(t, "Bad Request", ErrBadRequest.Msg)
+	assert.True(t, errors.Is(err, ErrBadRequest))
+	assert.True(t, errors.Is(err, internal))
+	assert.False(t, errors.Is(err, ErrNotFound))
+
+	err = ErrBadRequest.WithStatus(http.StatusNotFound)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", NewHTTPError(http.StatusNotFound, "user not found")), ErrNotFound))
+
+	// errors that are not predefined keep comparing by identity
+	custom := NewHTTPError(http.StatusNotFound, "user not found")
+	assert.False(t, errors.Is(NewHTTPError(http.StatusNotFound, "user not found"), custom))
+	assert.Same(t, custom, custom.SetInternal(internal))
+
+	ext := ErrForbidden.SetExtension("balance", 30)
+	assert.Nil(t, ErrForbidden.Extensions)
+	derived := ext.WithMessage("no credit")
+	derived.Extensions["balance"] = 0
+	assert.Equal(t, 30, ext.Extensions["balance"])
+}
+
+func TestHTTPError_SentinelRace(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			internal := fmt.Errorf("request %d", i)
+
+			err := ErrNotFound.SetInternal(internal)
+			assert.Equal(t, internal, err.Internal)
+
+			err = ErrNotFound.WithInternal(internal).SetType("https://example.com/probs/missing")
+			assert.Equal(t, internal, err.Internal)
+			assert.True(t, errors.Is(err, ErrNotFound))
+		}()
+	}
+	wg.Wait()
+
+	assert.Nil(t, ErrNotFound.Internal)
+	assert.Empty(t, ErrNotFound.Type)
+}
+
+func TestHTTPError_CodeAndFields(t *testing.T) {
+	field := FieldError{Field: "email", Source: "body", Rule: "required", Message: "email is required"}
+	err := ErrUnprocessableEntity.SetCode("invalid_user").AddFields(field)
+	assert.Empty(t, ErrUnprocessableEntity.Code)
+	assert.Empty(t, ErrUnprocessableEntity.Fields)
+
+	rec := httptest.NewRecorder()
+	DefaultErrHandlerFunc(err, rec)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{"msg":"Unprocessable Entity","code":"invalid_user",
+		"fields":[{"field":"email","source":"body","rule":"required","message":"email is required"}]}`, rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(HeaderAccept, MIMEApplicationXML)
+	rec = httptest.NewRecorder()
+	DefaultRequestErrHandlerFunc(err, rec, req)
+
+	assert.Contains(t, rec.Body.String(), "<code>invalid_user</code><fields><field><field>email</field><source>body</source>"+
+		"<rule>required</rule><message>email is required</message></field></fields>")
+}
@@ -0,0 +1,177 @@
+package httpz
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Group creates a new ServeMux for a specific URL prefix, allowing for route grouping.
+//
+// Routes of a group are registered on the root mux with the prefix joined to their
+// pattern, so r.Pattern and r.URL.Path keep the prefix and wildcards of the prefix,
+// such as /tenants/{tenant}/, can be read with r.PathValue or BindPathParams.
+func (sm *ServeMux) Group(prefix string) *ServeMux {
+	if len(prefix) == 0 {
+		sm.registry().fail(prefix, "len(prefix) must greater than 0")
+		prefix = "/"
+	}
+
+	if prefix[len(prefix)-1] != '/' {
+		sm.registry().fail(prefix, "the last char in the prefix must be /")
+		prefix += "/"
+	}
+
+	mux := &ServeMux{
+		root:      sm.rootMux(),
+		parent:    sm,
+		prefix:    sm.prefix + strings.TrimSuffix(prefix, "/"),
+		hostGroup: sm.hostGroup,
+	}
+	sm.registry().addGroup(mux)
+
+	return mux
+}
+
+// fullPattern joins the group prefix to a pattern relative to the mux.
+// The result is the pattern registered on the net/http mux.
+func (sm *ServeMux) fullPattern(pattern string) string {
+	method, host, path := parsePattern(pattern)
+	path = host + sm.prefix + path
+	if method == "" {
+		return path
+	}
+	return method + " " + path
+}
+
+// routePattern returns the pattern describing the route, which differs from
+// fullPattern by the host pattern of the host group.
+func (sm *ServeMux) routePattern(pattern string) string {
+	if sm.hostGroup == nil {
+		return sm.fullPattern(pattern)
+	}
+
+	method, _, path := parsePattern(pattern)
+	path = sm.hostGroup.host + sm.prefix + path
+	if method == "" {
+		return path
+	}
+	return method + " " + path
+}
+
+// wrapGroup wraps h with the middleware of the group and its parent groups.
+// Routes registered on the root mux are returned unchanged. In a host group
+// r.Pattern is set to the route pattern including the host.
+func (sm *ServeMux) wrapGroup(pattern string, h http.Handler) http.Handler {
+	if sm.root == nil {
+		return h
+	}
+
+	if sm.hostGroup != nil {
+		full, next := sm.routePattern(pattern), h
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Pattern = full
+			next.ServeHTTP(w, r)
+		})
+	}
+	return &groupHandler{group: sm, next: h}
+}
+
+// groupChain wraps next with the middleware of the group and its parent groups,
+// the outermost group running first.
+func (sm *ServeMux) groupChain(next http.Handler) http.Handler {
+	for g := sm; g.root != nil; g = g.parent {
+		g.mu.Lock()
+		mws := g.mws
+		g.mu.Unlock()
+
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+	}
+	return next
+}
+
+// groupHandler serves a group route through the middleware of its groups.
+// The chain is built on the first request and rebuilt after Use is called on a group.
+type groupHandler struct {
+	group  *ServeMux
+	next   http.Handler
+	cached atomic.Pointer[groupCache]
+}
+
+type groupCache struct {
+	gen uint64
+	h   http.Handler
+}
+
+func (gh *groupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gen := gh.group.root.groupGen.Load()
+
+	c := gh.cached.Load()
+	if c == nil || c.gen != gen {
+		c = &groupCache{gen: gen, h: gh.group.groupChain(gh.next)}
+		gh.cached.Store(c)
+	}
+
+	c.h.ServeHTTP(w, r)
+}
+
+// groupFor returns the deepest group of the host group hg whose prefix matches
+// the request, or scope itself when no group matches.
+func (sm *ServeMux) groupFor(r *http.Request, scope *ServeMux) *ServeMux {
+	best, depth := scope, -1
+	for _, g := range sm.registry().listGroups() {
+		if g.hostGroup != scope.hostGroup {
+			continue
+		}
+		segments := strings.Count(g.prefix, "/")
+		if segments > depth && matchPrefix(g.prefix, r.URL.Path) {
+			best, depth = g, segments
+		}
+	}
+	return best
+}
+
+// matchPrefix reports whether path starts with the group prefix followed by a slash.
+// Wildcards in the prefix match a single non-empty segment.
+func matchPrefix(prefix, path string) bool {
+	_, ok := prefixLen(prefix, path)
+	return ok
+}
+
+// prefixLen returns the length of the part of path matched by the group prefix,
+// and whether path starts with the prefix followed by a slash.
+func prefixLen(prefix, path string) (int, bool) {
+	rest := path
+	for prefix != "" {
+		prefix = prefix[1:]
+		if rest == "" || rest[0] != '/' {
+			return 0, false
+		}
+		rest = rest[1:]
+
+		var want, got string
+		want, prefix = cutSegment(prefix)
+		got, rest = cutSegment(rest)
+
+		if strings.HasPrefix(want, "{") && strings.HasSuffix(want, "}") {
+			if got == "" {
+				return 0, false
+			}
+			continue
+		}
+		if want != got {
+			return 0, false
+		}
+	}
+	return len(path) - len(rest), strings.HasPrefix(rest, "/")
+}
+
+// cutSegment splits s at the first slash, the slash stays in rest.
+func cutSegment(s string) (segment, rest string) {
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		return s[:i], s[i:]
+	}
+	return s, ""
+}
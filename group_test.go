@@ -0,0 +1,151 @@
+package httpz
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup_PatternAndPath(t *testing.T) {
+	mux := NewServeMux()
+	v1 := mux.Group("/v1/")
+	users := v1.Group("/users/")
+
+	users.Get("/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		fmt.Fprintf(w, "%s|%s|%s", r.Pattern, r.URL.Path, r.PathValue("id"))
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/7", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "GET /v1/users/{id}|/v1/users/7|7", rec.Body.String())
+}
+
+func TestGroup_BindPrefixParams(t *testing.T) {
+	type params struct {
+		Tenant string `param:"tenant"`
+		ID     int    `param:"id"`
+	}
+
+	mux := NewServeMux()
+	tenants := mux.Group("/tenants/{tenant}/")
+	tenants.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		var p params
+		if err := BindPathParams(r, &p); err != nil {
+			return err
+		}
+		return JSON(w, http.StatusOK, p)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/acme/users/3", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"Tenant":"acme","ID":3}`, rec.Body.String())
+}
+
+func TestGroup_Middleware(t *testing.T) {
+	buf := new(bytes.Buffer)
+	mark := func(s string) MiddlewareFunc {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				buf.WriteString(s)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	mux := NewServeMux()
+	mux.Use(mark("root"))
+
+	api := mux.Group("/api/")
+	api.Use(mark("api"))
+	v2 := api.Group("/v2/")
+	v2.Use(mark("v2"))
+
+	v2.Get("/hello", func(w http.ResponseWriter, r *http.Request) error {
+		buf.WriteString("[" + r.Pattern + "]")
+		return nil
+	})
+	mux.Get("/plain", listUsers)
+
+	serve := func(path string) string {
+		buf.Reset()
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		return buf.String()
+	}
+
+	assert.Equal(t, "rootapiv2[GET /api/v2/hello]", serve("/api/v2/hello"))
+	assert.Equal(t, "root", serve("/plain"))
+
+	// middleware added after the first request is picked up
+	api.Use(mark("late"))
+	assert.Equal(t, "rootapilatev2[GET /api/v2/hello]", serve("/api/v2/hello"))
+}
+
+func TestGroup_MethodNotAllowedAcrossGroups(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/api/status", listUsers)
+
+	api := mux.Group("/api/")
+	api.Post("/status", listUsers)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/status", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", rec.Header().Get(HeaderAllow))
+}
+
+func TestGroup_NotFoundWithPrefixParams(t *testing.T) {
+	mux := NewServeMux()
+	mux.NotFound(NotFoundHandler)
+
+	tenants := mux.Group("/tenants/{tenant}/")
+	tenants.NotFound(func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusNotFound, "no such tenant page")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/acme/nothing", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "no such tenant page", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/tenants", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.JSONEq(t, `{"msg":"Not Found"}`, rec.Body.String())
+}
+
+func TestMatchPrefix(t *testing.T) {
+	tests := []struct {
+		prefix string
+		path   string
+		want   bool
+	}{
+		{"/v1", "/v1/", true},
+		{"/v1", "/v1/users", true},
+		{"/v1", "/v1", false},
+		{"/v1", "/v10/users", false},
+		{"/v1/auth", "/v1/auth/login", true},
+		{"/tenants/{tenant}", "/tenants/acme/users", true},
+		{"/tenants/{tenant}", "/tenants//users", false},
+		{"/tenants/{tenant}", "/tenants/acme", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchPrefix(tt.prefix, tt.path), "%s %s", tt.prefix, tt.path)
+	}
+}
@@ -0,0 +1,157 @@
+package httpz
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RequestStats describes the response to a request, passed to the OnRequestEnd hooks.
+type RequestStats struct {
+	Status   int           // Status code sent, http.StatusOK when the handler wrote nothing
+	Bytes    int64         // Number of body bytes written
+	Duration time.Duration // Time spent serving the request
+}
+
+// hooks holds the hook functions of a mux tree. It is replaced as a whole when
+// a hook is added, so serving reads it without locking.
+type hooks struct {
+	route []func(RouteInfo)
+	start []func(r *http.Request)
+	end   []func(r *http.Request, stats RequestStats)
+	err   []func(r *http.Request, err error)
+}
+
+// OnRoute adds a hook called with the description of each route registered on
+// the mux tree, starting with the routes already registered. The route is not
+// yet named when the hook is called.
+func (sm *ServeMux) OnRoute(fn func(route RouteInfo)) {
+	reg := sm.registry()
+	reg.addHook(func(h *hooks) { h.route = append(h.route, fn) })
+
+	for _, route := range reg.list() {
+		fn(route)
+	}
+}
+
+// OnRequestStart adds a hook called when the mux tree starts serving a request,
+// before the middleware of the root mux.
+func (sm *ServeMux) OnRequestStart(fn func(r *http.Request)) {
+	sm.registry().addHook(func(h *hooks) { h.start = append(h.start, fn) })
+}
+
+// OnRequestEnd adds a hook called when the mux tree has served a request, with
+// the status code, the number of bytes written and the duration.
+// r.Pattern holds the pattern of the matched route, if any, even when the root
+// middleware served a copy of the request.
+func (sm *ServeMux) OnRequestEnd(fn func(r *http.Request, stats RequestStats)) {
+	sm.registry().addHook(func(h *hooks) { h.end = append(h.end, fn) })
+}
+
+// OnError adds a hook called with each error returned by a handler of the
+// mux tree, before the error handler.
+func (sm *ServeMux) OnError(fn func(r *http.Request, err error)) {
+	sm.registry().addHook(func(h *hooks) { h.err = append(h.err, fn) })
+}
+
+// addHook replaces the hooks with a copy modified by add.
+func (rr *routeRegistry) addHook(add func(h *hooks)) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	h := new(hooks)
+	if old := rr.hooks.Load(); old != nil {
+		*h = *old
+	}
+	add(h)
+	rr.hooks.Store(h)
+}
+
+// serveWithHooks serves the request with h, calling the request hooks around it.
+func serveWithHooks(h http.Handler, hk *hooks, w http.ResponseWriter, r *http.Request) {
+	for _, fn := range hk.start {
+		fn(r)
+	}
+
+	if len(hk.end) == 0 {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	m := new(matchedRoute)
+	r = r.WithContext(context.WithValue(r.Context(), matchedRouteKey{}, m))
+
+	start := time.Now()
+	sw := &statsWriter{HelperResponseWriter: NewHelperRW(w)}
+	h.ServeHTTP(sw, r)
+
+	if r.Pattern == "" {
+		r.Pattern = m.pattern
+	}
+	stats := RequestStats{Status: sw.status, Bytes: sw.bytes, Duration: time.Since(start)}
+	if stats.Status == 0 {
+		stats.Status = http.StatusOK
+	}
+	for _, fn := range hk.end {
+		fn(r, stats)
+	}
+}
+
+// matchedRouteKey is the context key of the matchedRoute of a request.
+type matchedRouteKey struct{}
+
+// matchedRoute carries the pattern of the route serving a request back to the
+// OnRequestEnd hooks, as middleware may route a copy of the request.
+type matchedRoute struct {
+	pattern string
+}
+
+// serveRoute serves the request with mux and records the pattern of the matched
+// route for the OnRequestEnd hooks.
+func serveRoute(mux *http.ServeMux, w http.ResponseWriter, r *http.Request) {
+	mux.ServeHTTP(w, r)
+	if m, ok := r.Context().Value(matchedRouteKey{}).(*matchedRoute); ok {
+		m.pattern = r.Pattern
+	}
+}
+
+// statsWriter records the status code and the number of bytes written.
+type statsWriter struct {
+	*HelperResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader records the first final status code.
+func (sw *statsWriter) WriteHeader(code int) {
+	if sw.status == 0 && code >= http.StatusOK {
+		sw.status = code
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the number of bytes written.
+func (sw *statsWriter) Write(b []byte) (int, error) {
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	n, err := sw.ResponseWriter.Write(b)
+	sw.bytes += int64(n)
+	return n, err
+}
+
+// ReadFrom records the number of bytes copied, and keeps the io.ReaderFrom
+// optimization of the underlying writer, such as sendfile.
+func (sw *statsWriter) ReadFrom(src io.Reader) (n int64, err error) {
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	if rf, ok := sw.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		n, err = io.Copy(sw.ResponseWriter, src)
+	}
+	sw.bytes += n
+	return n, err
+}
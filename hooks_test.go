@@ -0,0 +1,122 @@
+package httpz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMux_OnRoute(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/users", listUsers)
+
+	var patterns []string
+	mux.OnRoute(func(route RouteInfo) {
+		patterns = append(patterns, route.Pattern)
+	})
+
+	api := mux.Group("/api/")
+	api.Post("/users", listUsers)
+
+	assert.Equal(t, []string{"GET /users", "POST /api/users"}, patterns)
+}
+
+func TestServeMux_OnRequest(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusCreated, "hello")
+	})
+	mux.Get("/empty", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	var events []string
+	var stats RequestStats
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			events = append(events, "middleware")
+			next.ServeHTTP(w, r)
+		})
+	})
+	mux.OnRequestStart(func(r *http.Request) {
+		events = append(events, "start "+r.URL.Path)
+	})
+	mux.OnRequestEnd(func(r *http.Request, s RequestStats) {
+		events = append(events, "end "+r.Pattern)
+		stats = s
+	})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	assert.Equal(t, []string{"start /users/1", "middleware", "end GET /users/{id}"}, events)
+	assert.Equal(t, http.StatusCreated, stats.Status)
+	assert.Equal(t, int64(5), stats.Bytes)
+	assert.Positive(t, stats.Duration)
+	assert.Equal(t, "hello", rec.Body.String())
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/empty", nil))
+	assert.Equal(t, http.StatusOK, stats.Status)
+	assert.Zero(t, stats.Bytes)
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, stats.Status)
+}
+
+func TestServeMux_OnError(t *testing.T) {
+	boom := errors.New("boom")
+	mux := NewServeMux()
+	api := mux.Group("/api/")
+	api.Get("/fail", func(w http.ResponseWriter, r *http.Request) error {
+		return boom
+	})
+
+	var got []string
+	api.OnError(func(r *http.Request, err error) {
+		got = append(got, "hook "+r.URL.Path+" "+err.Error())
+	})
+	mux.RequestErrHandlerFunc = func(err error, w http.ResponseWriter, r *http.Request) {
+		got = append(got, "handler "+err.Error())
+	}
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/fail", nil))
+	assert.Equal(t, []string{"hook /api/fail boom", "handler boom"}, got)
+}
+
+func TestServeMux_OnRequestEndPattern(t *testing.T) {
+	type ctxKey struct{}
+
+	mux := NewServeMux()
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, "v")))
+		})
+	})
+	api := mux.Group("/api/")
+	api.Get("/files/{name}", func(w http.ResponseWriter, r *http.Request) error {
+		_, ok := w.(io.ReaderFrom)
+		assert.True(t, ok)
+		_, err := io.Copy(w, strings.NewReader("hello world"))
+		return err
+	})
+
+	var pattern string
+	var stats RequestStats
+	mux.OnRequestEnd(func(r *http.Request, s RequestStats) {
+		pattern, stats = r.Pattern, s
+	})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/files/a.txt", nil))
+
+	assert.Equal(t, "GET /api/files/{name}", pattern)
+	assert.Equal(t, http.StatusOK, stats.Status)
+	assert.Equal(t, int64(11), stats.Bytes)
+	assert.Equal(t, "hello world", rec.Body.String())
+}
@@ -0,0 +1,113 @@
+package httpz
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Host creates a new ServeMux whose routes only match requests for the given host.
+// The host may contain wildcards for whole labels, such as {tenant}.example.com;
+// their values are read with r.PathValue and bound by BindPathParams like path params.
+//
+//	api := mux.Host("api.example.com")
+//	tenant := mux.Host("{tenant}.example.com")
+//	tenant.Get("/users/{id}", h) // r.Pattern is "GET {tenant}.example.com/users/{id}"
+//
+// A request whose host matches a host group is served by the routes of that group, and
+// by the routes of the root when none of them matches, such as a GET /healthz shared by
+// every host. The NotFound and MethodNotAllowed handlers of the host group answer the
+// requests no route matches. Literal hosts are tried before hosts with wildcards, and
+// ports are ignored.
+func (sm *ServeMux) Host(host string) *ServeMux {
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		sm.registry().fail(host, "invalid host "+host)
+	}
+
+	if sm.hostGroup != nil {
+		sm.registry().fail(host, "host groups can not be nested")
+	}
+
+	mux := &ServeMux{
+		root:   sm.rootMux(),
+		parent: sm,
+		prefix: sm.prefix,
+		host:   strings.ToLower(host),
+	}
+	mux.hostGroup = mux
+	mux.ServeMux.Handle(catchAllPattern, http.HandlerFunc(mux.serveUnmatched))
+
+	sm.registry().addGroup(mux)
+	sm.registry().addHost(mux)
+
+	return mux
+}
+
+// routeMux returns the net/http mux the routes of this mux are registered on.
+func (sm *ServeMux) routeMux() *http.ServeMux {
+	if sm.hostGroup != nil {
+		return &sm.hostGroup.ServeMux
+	}
+	return &sm.rootMux().ServeMux
+}
+
+// hostFor returns the host group matching the request and sets the host params on r.
+// It returns nil when no host group matches.
+func (sm *ServeMux) hostFor(r *http.Request) *ServeMux {
+	if !sm.registry().hosted.Load() {
+		return nil
+	}
+	hosts := sm.registry().listHosts()
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	host := strings.ToLower(stripPort(r.Host))
+	for _, wildcards := range []bool{false, true} {
+		for _, hg := range hosts {
+			if strings.Contains(hg.host, "{") != wildcards {
+				continue
+			}
+			if params, ok := matchHost(hg.host, host); ok {
+				for i := 0; i < len(params); i += 2 {
+					r.SetPathValue(params[i], params[i+1])
+				}
+				return hg
+			}
+		}
+	}
+	return nil
+}
+
+// matchHost matches a host against a host pattern and returns the wildcard
+// values as name/value pairs.
+func matchHost(pattern, host string) ([]string, bool) {
+	want := strings.Split(pattern, ".")
+	got := strings.Split(host, ".")
+	if len(want) != len(got) {
+		return nil, false
+	}
+
+	var params []string
+	for i, label := range want {
+		if strings.HasPrefix(label, "{") && strings.HasSuffix(label, "}") {
+			if got[i] == "" {
+				return nil, false
+			}
+			params = append(params, label[1:len(label)-1], got[i])
+			continue
+		}
+		if label != got[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// stripPort removes the port from a host, if any.
+func stripPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
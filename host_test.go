@@ -0,0 +1,144 @@
+package httpz
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMux_Host(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/users", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, "default")
+	})
+
+	api := mux.Host("api.example.com")
+	api.Get("/users", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, "api")
+	})
+
+	tenant := mux.Host("{tenant}.example.com")
+	tenant.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, fmt.Sprintf("%s|%s|%s", r.Pattern, r.PathValue("tenant"), r.PathValue("id")))
+	})
+
+	tests := []struct {
+		host string
+		path string
+		code int
+		body string
+	}{
+		{"example.com", "/users", http.StatusOK, "default"},
+		{"api.example.com", "/users", http.StatusOK, "api"},
+		{"API.example.com:8080", "/users", http.StatusOK, "api"},
+		{"acme.example.com", "/users/1", http.StatusOK, "GET {tenant}.example.com/users/{id}|acme|1"},
+		{"acme.example.com", "/users", http.StatusOK, "default"},
+		{"acme.example.com", "/orgs", http.StatusNotFound, "404 page not found\n"},
+		{"a.b.example.com", "/users/1", http.StatusNotFound, "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+
+	routes := mux.Routes()
+	assert.Equal(t, "{tenant}.example.com", routes[2].Host)
+	assert.Equal(t, "/users/{id}", routes[2].Path)
+	assert.Equal(t, "GET {tenant}.example.com/users/{id}", routes[2].Pattern)
+}
+
+func TestServeMux_HostBind(t *testing.T) {
+	type params struct {
+		Tenant string `param:"tenant"`
+		Org    string `param:"org"`
+		ID     int    `param:"id"`
+	}
+
+	mux := NewServeMux()
+	orgs := mux.Host("{tenant}.example.com").Group("/orgs/{org}/")
+	orgs.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		var p params
+		if err := BindPathParams(r, &p); err != nil {
+			return err
+		}
+		return JSON(w, http.StatusOK, p)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orgs/dev/users/9", nil)
+	req.Host = "acme.example.com"
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"Tenant":"acme","Org":"dev","ID":9}`, rec.Body.String())
+}
+
+func TestServeMux_HostFallback(t *testing.T) {
+	mux := NewServeMux()
+	mux.NotFound(NotFoundHandler)
+
+	api := mux.Host("api.example.com")
+	api.NotFound(func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusNotFound, "api not found")
+	})
+	api.Post("/users", listUsers)
+	mux.Get("/healthz", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, "ok")
+	})
+	mux.Delete("/users", listUsers)
+
+	// routes of the root serve the requests no route of the host matches
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Host = "api.example.com"
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/nothing", nil)
+	req.Host = "api.example.com"
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "api not found", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodOptions, "/users", nil)
+	req.Host = "api.example.com"
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "DELETE, OPTIONS, POST", rec.Header().Get(HeaderAllow))
+
+	req = httptest.NewRequest(http.MethodGet, "/nothing", nil)
+	req.Host = "www.example.com"
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.JSONEq(t, `{"msg":"Not Found"}`, rec.Body.String())
+}
+
+func TestMatchHost(t *testing.T) {
+	params, ok := matchHost("{tenant}.{region}.example.com", "acme.eu.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"tenant", "acme", "region", "eu"}, params)
+
+	_, ok = matchHost("{tenant}.example.com", "example.com")
+	assert.False(t, ok)
+
+	_, ok = matchHost("api.example.com", "www.example.com")
+	assert.False(t, ok)
+}
@@ -3,9 +3,12 @@
 package httpz
 
 import (
-	"fmt"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // HandlerFunc defines the function signature for a handler.
@@ -23,114 +26,243 @@ type RouteMiddlewareFunc func(next HandlerFunc) HandlerFunc
 // ServeMux embeds http.ServeMux and provides additional features like error handling and middleware.
 type ServeMux struct {
 	http.ServeMux
-	ErrHandlerFunc ErrHandlerFunc   // Function for centralized error handling
-	mws            []MiddlewareFunc // List of middleware functions
+	// ErrHandlerFunc is the legacy centralized error handler, DefaultErrHandlerFunc
+	// by default. When set to another function it takes precedence over
+	// RequestErrHandlerFunc, which keeps existing code working.
+	ErrHandlerFunc ErrHandlerFunc
+	// RequestErrHandlerFunc is the request-aware centralized error handler.
+	// A group uses the error handlers of its closest parent setting one, looked
+	// up when the error occurs, unless it sets its own.
+	RequestErrHandlerFunc RequestErrHandlerFunc
+	// RecoverPanics converts panics in the handlers of the mux and its groups
+	// into errors passed to the centralized error handler, see PanicError.
+	RecoverPanics bool
+	// TrailingSlash sets how a request path differing from a route by its
+	// trailing slash is served, only the setting of the root mux is used.
+	TrailingSlash TrailingSlashPolicy
+	// RedirectCase redirects a request path differing from a route only by its
+	// letter case to the registered casing, only the setting of the root mux is used.
+	RedirectCase bool
+
+	mu        sync.Mutex                    // Guards mws and rebuilding handler
+	mws       []MiddlewareFunc              // List of middleware functions
+	handler   atomic.Pointer[http.Handler]  // mws applied to the mux, nil until the first request
+	catchAll  atomic.Pointer[catchAllRoute] // Route matching every request, only set on the root and host groups
+	groupGen  atomic.Uint64                 // Incremented when group middleware changes, only used on the root
+	root      *ServeMux                     // Root mux of the tree, nil for the root itself
+	parent    *ServeMux                     // Mux the group was created from, nil for the root
+	prefix    string                        // Full group prefix without the trailing slash, routes are registered on the root with it
+	host      string                        // Host pattern of a host group, see Host
+	hostGroup *ServeMux                     // Host group the mux belongs to, nil outside host groups
+	reg       *routeRegistry                // Registered routes, only set on the root
+	regOnce   sync.Once
+
+	notFound         HandlerFunc // Called when no route matches, see NotFound
+	methodNotAllowed HandlerFunc // Called when only the method does not match, see MethodNotAllowed
 }
 
 // NewServeMux returns a new instance of ServeMux with default settings.
 func NewServeMux() *ServeMux {
 	return &ServeMux{
-		ServeMux:       http.ServeMux{},
-		ErrHandlerFunc: DefaultErrHandlerFunc,
+		ServeMux:              http.ServeMux{},
+		ErrHandlerFunc:        DefaultErrHandlerFunc,
+		RequestErrHandlerFunc: DefaultRequestErrHandlerFunc,
 	}
 }
 
 // HandleFunc registers a new route with a pattern and a handler function.
 // The handler function can return an error for centralized error handling.
-func (sm *ServeMux) HandleFunc(pattern string, h HandlerFunc) {
-	sm.ServeMux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
-		err := h(w, r)
+func (sm *ServeMux) HandleFunc(pattern string, h HandlerFunc) *Route {
+	return sm.handle(pattern, h)
+}
+
+// Handle registers a standard http.Handler for the given pattern.
+func (sm *ServeMux) Handle(pattern string, h http.Handler) *Route {
+	info := sm.routeInfo(pattern, h, nil)
+	if !sm.register(pattern, sm.wrapGroup(pattern, h)) {
+		return detached(info)
+	}
+	return sm.registry().add(info)
+}
+
+// handle registers h wrapped with the route-specific middleware and records the route.
+func (sm *ServeMux) handle(pattern string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.handleRoute(pattern, h, m, sm.routeInfo(pattern, h, m))
+}
+
+// handleRoute registers h wrapped with the route-specific middleware and records
+// the route described by info.
+func (sm *ServeMux) handleRoute(pattern string, h HandlerFunc, m []RouteMiddlewareFunc, info RouteInfo) *Route {
+	if !sm.register(pattern, sm.wrapGroup(pattern, sm.serveFunc(use(h, m...)))) {
+		return detached(info)
+	}
+	return sm.registry().add(info)
+}
+
+// serveFunc converts h to an http.Handler that passes the returned error,
+// or the recovered panic when RecoverPanics is enabled, to the error handler.
+func (sm *ServeMux) serveFunc(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := sm.call(h, w, r)
 
 		if err != nil {
-			sm.ErrHandlerFunc(err, w)
+			sm.handleError(err, w, r)
 		}
 	})
 }
 
+// handleError passes err to the OnError hooks, then maps it with the error mappings
+// and passes it to the error handler of the mux or of its closest parent setting one.
+func (sm *ServeMux) handleError(err error, w http.ResponseWriter, r *http.Request) {
+	reg := sm.registry()
+	if hk := reg.hooks.Load(); hk != nil {
+		for _, fn := range hk.err {
+			fn(r, err)
+		}
+	}
+
+	err = reg.mapError(err)
+	for mux := sm; mux != nil; mux = mux.parent {
+		switch {
+		case mux.ErrHandlerFunc != nil && !isDefaultErrHandler(mux.ErrHandlerFunc):
+			mux.ErrHandlerFunc(err, w)
+			return
+		case mux.RequestErrHandlerFunc != nil:
+			mux.RequestErrHandlerFunc(err, w, r)
+			return
+		}
+	}
+	DefaultRequestErrHandlerFunc(err, w, r)
+}
+
+// isDefaultErrHandler reports whether fn is DefaultErrHandlerFunc, which only
+// stands for the legacy default and gives way to RequestErrHandlerFunc.
+func isDefaultErrHandler(fn ErrHandlerFunc) bool {
+	return reflect.ValueOf(fn).Pointer() == reflect.ValueOf(DefaultErrHandlerFunc).Pointer()
+}
+
 // ServeHTTP processes HTTP requests using the registered handlers and middleware.
+// The middleware chain is built on the first request and reused afterwards.
+// Calling ServeHTTP on a group serves the request with the root mux.
 func (sm *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	h := http.Handler(&sm.ServeMux)
+	if sm.root != nil {
+		sm.root.ServeHTTP(w, r)
+		return
+	}
+
+	h := sm.handler.Load()
+	if h == nil {
+		h = sm.buildHandler()
+	}
+
+	if hk := sm.registry().hooks.Load(); hk != nil && (len(hk.start) > 0 || len(hk.end) > 0) {
+		serveWithHooks(*h, hk, w, r)
+		return
+	}
+
+	(*h).ServeHTTP(w, r)
+}
 
+// buildHandler wraps the mux with its middleware and caches the result.
+func (sm *ServeMux) buildHandler() *http.Handler {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if h := sm.handler.Load(); h != nil {
+		return h
+	}
+	sm.registry() // registers the catch-all pattern
+
+	h := http.Handler(http.HandlerFunc(sm.dispatch))
 	for i := len(sm.mws) - 1; i >= 0; i-- {
 		h = sm.mws[i](h)
 	}
 
-	h.ServeHTTP(w, r)
+	sm.handler.Store(&h)
+	return &h
 }
 
-// Group creates a new ServeMux for a specific URL prefix, allowing for route grouping.
-func (sm *ServeMux) Group(prefix string) *ServeMux {
-	if len(prefix) == 0 {
-		panic("len(prefix) must greater than 0")
+// rootMux returns the root mux of the tree, which holds every route.
+func (sm *ServeMux) rootMux() *ServeMux {
+	if sm.root != nil {
+		return sm.root
 	}
+	return sm
+}
 
-	if prefix[len(prefix)-1] != '/' {
-		panic("the last char in the prefix must be /")
-	}
+// Method registers a new route for an arbitrary method with optional route-specific middleware.
+// It can be used for methods without a helper, such as PROPFIND or REPORT.
+func (sm *ServeMux) Method(method, path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.method(method, path, h, m, sm.routeInfo(method+" "+path, h, m))
+}
 
-	mux := &ServeMux{
-		ServeMux:       http.ServeMux{},
-		ErrHandlerFunc: sm.ErrHandlerFunc,
+// method registers h for the method and path like Method, and records the route
+// described by info.
+func (sm *ServeMux) method(method, path string, h HandlerFunc, m []RouteMiddlewareFunc, info RouteInfo) *Route {
+	if method == "" || strings.ContainsAny(method, " \t") {
+		sm.registry().fail(method+" "+path, "invalid method "+strconv.Quote(method))
+		return detached(info)
 	}
+	return sm.handleRoute(method+" "+path, h, m, info)
+}
 
-	pre := strings.TrimSuffix(prefix, "/")
-	sm.Handle(prefix, http.StripPrefix(pre, mux))
+// Match registers the same handler for several methods and returns one route per method.
+func (sm *ServeMux) Match(methods []string, path string, h HandlerFunc, m ...RouteMiddlewareFunc) []*Route {
+	routes := make([]*Route, 0, len(methods))
+	for _, method := range methods {
+		routes = append(routes, sm.Method(method, path, h, m...))
+	}
+	return routes
+}
 
-	return mux
+// Any registers a new route matching every method with optional route-specific middleware.
+func (sm *ServeMux) Any(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.handle(path, h, m...)
 }
 
 // Get registers a new GET route with optional route-specific middleware.
-func (sm *ServeMux) Get(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodGet, path), h)
+func (sm *ServeMux) Get(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodGet, path, h, m...)
 }
 
 // Head registers a new HEAD route with optional route-specific middleware.
-func (sm *ServeMux) Head(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodHead, path), h)
+func (sm *ServeMux) Head(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodHead, path, h, m...)
 }
 
 // Post registers a new POST route with optional route-specific middleware.
-func (sm *ServeMux) Post(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodPost, path), h)
+func (sm *ServeMux) Post(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodPost, path, h, m...)
 }
 
 // Put registers a new PUT route with optional route-specific middleware.
-func (sm *ServeMux) Put(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodGet, path), h)
+func (sm *ServeMux) Put(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodPut, path, h, m...)
 }
 
 // Patch registers a new PATCH route with optional route-specific middleware.
-func (sm *ServeMux) Patch(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodPatch, path), h)
+func (sm *ServeMux) Patch(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodPatch, path, h, m...)
 }
 
 // Delete registers a new DELETE route with optional route-specific middleware.
-func (sm *ServeMux) Delete(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodDelete, path), h)
+func (sm *ServeMux) Delete(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodDelete, path, h, m...)
 }
 
 // Connect registers a new CONNECT route with optional route-specific middleware.
-func (sm *ServeMux) Connect(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodConnect, path), h)
+func (sm *ServeMux) Connect(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodConnect, path, h, m...)
 }
 
 // Options registers a new OPTIONS route with optional route-specific middleware.
-func (sm *ServeMux) Options(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodOptions, path), h)
+func (sm *ServeMux) Options(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodOptions, path, h, m...)
 }
 
 // Trace registers a new TRACE route with optional route-specific middleware.
-func (sm *ServeMux) Trace(path string, h HandlerFunc, m ...RouteMiddlewareFunc) {
-	h = use(h, m...)
-	sm.HandleFunc(fmt.Sprintf("%s %s", http.MethodTrace, path), h)
+func (sm *ServeMux) Trace(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route {
+	return sm.Method(http.MethodTrace, path, h, m...)
 }
 
 // use applies route-specific middleware to a handler function.
@@ -143,8 +275,22 @@ func use(h HandlerFunc, m ...RouteMiddlewareFunc) HandlerFunc {
 }
 
 // Use adds middleware to the ServeMux, which will be applied to all routes.
+// Middleware of the root mux runs before routing, middleware of a group runs
+// after routing, so r.Pattern and path values are available to it.
+//
+// Use is meant to be called while setting up the mux, but it is safe to call it
+// after serving has started: the chain is rebuilt for requests that arrive after
+// Use returns, while requests already in flight finish with the previous chain.
 func (sm *ServeMux) Use(m ...MiddlewareFunc) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
 	sm.mws = append(sm.mws, m...)
+	if sm.root != nil {
+		sm.root.groupGen.Add(1)
+		return
+	}
+	sm.handler.Store(nil)
 }
 
 // Adator converts a standard http.HandlerFunc to a HandlerFunc that returns an error.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -218,3 +219,282 @@ func TestServeMux_Adator(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Equal(t, "adator", rec.Body.String())
 }
+
+func TestRequestErrHandler(t *testing.T) {
+	mux := NewServeMux()
+	mux.RequestErrHandlerFunc = func(err error, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprint(w, r.URL.Path)
+	}
+
+	api := mux.Group("/api/")
+	api.Get("/fail", func(w http.ResponseWriter, r *http.Request) error {
+		return ErrBadRequest
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/fail", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "/api/fail", rec.Body.String())
+
+	// the legacy handler keeps precedence when it is set
+	mux.ErrHandlerFunc = func(err error, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusConflict)
+	}
+	mux.Get("/legacy", func(w http.ResponseWriter, r *http.Request) error {
+		return ErrBadRequest
+	})
+
+	req = httptest.NewRequest(http.MethodGet, "/legacy", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestRequestErrHandler_SetAfterGroup(t *testing.T) {
+	mux := NewServeMux()
+	api := mux.Group("/api/")
+	v1 := api.Group("/v1/")
+	host := mux.Host("api.example.com")
+	fail := func(w http.ResponseWriter, r *http.Request) error {
+		return ErrBadRequest
+	}
+	v1.Get("/fail", fail)
+	host.Get("/fail", fail)
+
+	mux.RequestErrHandlerFunc = ProblemErrHandlerFunc
+
+	for _, target := range []string{"/api/v1/fail", "http://api.example.com/fail"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(HeaderContentType), target)
+	}
+
+	// a group setting its own handler overrides its parents
+	api.RequestErrHandlerFunc = func(err error, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/fail", nil))
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
+
+func TestRequestErrHandler_WrapLegacy(t *testing.T) {
+	mux := NewServeMux()
+	assert.NotNil(t, mux.ErrHandlerFunc)
+
+	var logged error
+	orig := mux.ErrHandlerFunc
+	mux.ErrHandlerFunc = func(err error, w http.ResponseWriter) {
+		logged = err
+		orig(err, w)
+	}
+	mux.Get("/fail", func(w http.ResponseWriter, r *http.Request) error {
+		return ErrBadRequest
+	})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	assert.Equal(t, ErrBadRequest, logged)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"msg":"Bad Request"}`, rec.Body.String())
+}
+
+func TestUseAfterServe(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, rec.Header().Get("X-Late"))
+
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Late", "1")
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "1", rec.Header().Get("X-Late"))
+}
+
+func TestUseConcurrentWithServe(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		}()
+		go func() {
+			defer wg.Done()
+			mux.Use(func(next http.Handler) http.Handler { return next })
+		}()
+	}
+	wg.Wait()
+}
+
+func benchmarkMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newBenchmarkMux() *ServeMux {
+	mux := NewServeMux()
+	for i := 0; i < 5; i++ {
+		mux.Use(benchmarkMiddleware)
+	}
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+	return mux
+}
+
+func BenchmarkServeMux_Middleware(b *testing.B) {
+	mux := newBenchmarkMux()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mux.ServeHTTP(w, req)
+	}
+}
+
+// BenchmarkServeMux_MiddlewarePerRequest wraps the chain on every request,
+// which is what ServeHTTP did before the chain was cached.
+func BenchmarkServeMux_MiddlewarePerRequest(b *testing.B) {
+	mux := newBenchmarkMux()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := http.Handler(&mux.ServeMux)
+		for j := len(mux.mws) - 1; j >= 0; j-- {
+			h = mux.mws[j](h)
+		}
+		h.ServeHTTP(w, req)
+	}
+}
+
+func TestMethodHelpers(t *testing.T) {
+	type register func(path string, h HandlerFunc, m ...RouteMiddlewareFunc) *Route
+
+	mux := NewServeMux()
+	helpers := map[string]register{
+		http.MethodGet:     mux.Get,
+		http.MethodHead:    mux.Head,
+		http.MethodPost:    mux.Post,
+		http.MethodPut:     mux.Put,
+		http.MethodPatch:   mux.Patch,
+		http.MethodDelete:  mux.Delete,
+		http.MethodConnect: mux.Connect,
+		http.MethodOptions: mux.Options,
+		http.MethodTrace:   mux.Trace,
+	}
+
+	for method, fn := range helpers {
+		route := fn("/"+method, func(w http.ResponseWriter, r *http.Request) error {
+			fmt.Fprint(w, r.Method)
+			return nil
+		})
+		assert.Equal(t, method, route.Info().Method)
+	}
+
+	for method := range helpers {
+		t.Run(method, func(t *testing.T) {
+			req := httptest.NewRequest(method, "/"+method, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, method, rec.Body.String())
+		})
+	}
+}
+
+func TestMuxMethod(t *testing.T) {
+	mux := NewServeMux()
+	mux.Method(PROPFIND, "/dav/{path...}", func(w http.ResponseWriter, r *http.Request) error {
+		fmt.Fprint(w, r.PathValue("path"))
+		return nil
+	})
+
+	req := httptest.NewRequest(PROPFIND, "/dav/a/b", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a/b", rec.Body.String())
+
+	req = httptest.NewRequest(REPORT, "/dav/a/b", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	assert.Panics(t, func() {
+		mux.Method("", "/empty", listUsers)
+	})
+}
+
+func TestMuxMatch(t *testing.T) {
+	mux := NewServeMux()
+	routes := mux.Match([]string{http.MethodPut, REPORT}, "/match", func(w http.ResponseWriter, r *http.Request) error {
+		fmt.Fprint(w, r.Method)
+		return nil
+	})
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "PUT /match", routes[0].Info().Pattern)
+	assert.Equal(t, "REPORT /match", routes[1].Info().Pattern)
+
+	for _, method := range []string{http.MethodPut, REPORT} {
+		req := httptest.NewRequest(method, "/match", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, method, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/match", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestMuxAny(t *testing.T) {
+	mux := NewServeMux()
+	route := mux.Any("/any", func(w http.ResponseWriter, r *http.Request) error {
+		fmt.Fprint(w, r.Method)
+		return nil
+	})
+	assert.Equal(t, "", route.Info().Method)
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete, PROPFIND} {
+		req := httptest.NewRequest(method, "/any", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, method, rec.Body.String())
+	}
+}
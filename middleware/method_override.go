@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// MethodOverride is a middleware that lets POST requests ask for another method,
+// for HTML forms and proxies that only allow GET and POST. The method is read from
+// the X-HTTP-Method-Override header, or from the _method field of a form body,
+// and must be one of methods, PUT, PATCH and DELETE by default. Other requests
+// are left unchanged.
+//
+// The method has to be overridden before routing for method patterns such as
+// "DELETE /users/{id}" to match, so register it on the root mux:
+//
+//	mux := httpz.NewServeMux()
+//	mux.Use(middleware.MethodOverride())
+//	mux.Delete("/users/{id}", deleteUser)
+func MethodOverride(methods ...string) func(next http.Handler) http.Handler {
+	if len(methods) == 0 {
+		methods = []string{http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	allowed := make([]string, len(methods))
+	for i, m := range methods {
+		allowed[i] = strings.ToUpper(m)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				method := r.Header.Get("X-HTTP-Method-Override")
+				if method == "" && isForm(r) {
+					method = r.PostFormValue("_method")
+				}
+
+				if method = strings.ToUpper(strings.TrimSpace(method)); slices.Contains(allowed, method) {
+					r.Method = method
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isForm reports whether the request body is a form.
+func isForm(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data"
+}
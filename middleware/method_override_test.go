@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aeilang/httpz"
+)
+
+func TestMethodOverride(t *testing.T) {
+	t.Parallel()
+
+	mux := httpz.NewServeMux()
+	mux.Use(MethodOverride())
+	mux.Delete("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return httpz.String(w, http.StatusOK, "deleted "+r.PathValue("id"))
+	})
+	mux.Post("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return httpz.String(w, http.StatusOK, "posted "+r.PathValue("id"))
+	})
+	mux.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return httpz.String(w, http.StatusOK, "got "+r.PathValue("id"))
+	})
+
+	var tests = []struct {
+		name        string
+		method      string
+		header      string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"header", http.MethodPost, "DELETE", "", "", "deleted 1"},
+		{"header lower case", http.MethodPost, "delete", "", "", "deleted 1"},
+		{"form field", http.MethodPost, "", "application/x-www-form-urlencoded", "_method=DELETE", "deleted 1"},
+		{"header wins", http.MethodPost, "POST", "application/x-www-form-urlencoded", "_method=DELETE", "posted 1"},
+		{"not allowed", http.MethodPost, "GET", "", "", "posted 1"},
+		{"not a form", http.MethodPost, "", "text/plain", "_method=DELETE", "posted 1"},
+		{"not post", http.MethodGet, "DELETE", "", "", "got 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/users/1", strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set(httpz.HeaderXHTTPMethodOverride, tt.header)
+			}
+			if tt.contentType != "" {
+				req.Header.Set(httpz.HeaderContentType, tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMethodOverrideAllowList(t *testing.T) {
+	t.Parallel()
+
+	mux := httpz.NewServeMux()
+	mux.Use(MethodOverride("patch"))
+	mux.Patch("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return httpz.String(w, http.StatusOK, "patched")
+	})
+	mux.Post("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return httpz.String(w, http.StatusOK, "posted")
+	})
+
+	for method, want := range map[string]string{"PATCH": "patched", "DELETE": "posted"} {
+		req := httptest.NewRequest(http.MethodPost, "/users/1", nil)
+		req.Header.Set(httpz.HeaderXHTTPMethodOverride, method)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if got := w.Body.String(); got != want {
+			t.Errorf("%s: got %q, want %q", method, got, want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package httpz
+
+import (
+	"encoding"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIInfo holds the metadata of an OpenAPI document.
+type OpenAPIInfo struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// OpenAPI registers a GET route serving the OpenAPI 3.1 document of the mux as JSON.
+// The document is generated on each request, so routes registered later are included.
+//
+//	mux.OpenAPI("/openapi.json", httpz.OpenAPIInfo{Title: "Users API", Version: "1.0.0"})
+func (sm *ServeMux) OpenAPI(path string, info OpenAPIInfo) *Route {
+	self := openAPIPath(sm.prefix + path)
+	return sm.Get(path, func(w http.ResponseWriter, r *http.Request) error {
+		doc := sm.rootMux().OpenAPIDocument(info)
+
+		// the document does not describe itself
+		paths := doc["paths"].(Map)
+		if item, ok := paths[self].(Map); ok {
+			delete(item, "get")
+			if len(item) == 0 {
+				delete(paths, self)
+			}
+		}
+		return JSON(w, http.StatusOK, doc)
+	})
+}
+
+// OpenAPIDocument generates an OpenAPI 3.1 document from the registered routes.
+//
+// Paths and methods come from the route patterns. Parameters come from the path
+// wildcards and, for routes registered with HandleTyped, from the param and query
+// tags of the request type, whose other fields form the request body. Query
+// parameters are only listed for the methods Bind reads them for.
+// The response type gives the success response, and errors are described by the
+// JSON body of HTTPError. Routes matching any method, and routes for methods
+// OpenAPI has no operation for, such as PROPFIND or CONNECT, are left out.
+func (sm *ServeMux) OpenAPIDocument(info OpenAPIInfo) Map {
+	g := &openAPIGen{schemas: Map{}, names: map[reflect.Type]string{}}
+	g.schemas["HTTPError"] = Map{
+		"type": "object",
+		"properties": Map{
+			"msg":    Map{"type": "string"},
+			"code":   Map{"type": "string"},
+			"fields": g.schema(reflect.TypeFor[[]FieldError]()),
+		},
+	}
+
+	paths := Map{}
+	for _, route := range sm.Routes() {
+		if !openAPIMethods[route.Method] {
+			continue
+		}
+
+		path := openAPIPath(route.Path)
+		item, ok := paths[path].(Map)
+		if !ok {
+			item = Map{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = g.operation(route)
+	}
+
+	doc := Map{
+		"openapi": "3.1.0",
+		"info": Map{
+			"title":   info.Title,
+			"version": info.Version,
+		},
+		"paths":      paths,
+		"components": Map{"schemas": g.schemas},
+	}
+	if info.Description != "" {
+		doc["info"].(Map)["description"] = info.Description
+	}
+	return doc
+}
+
+// openAPIMethods are the methods a path item can describe.
+var openAPIMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodPut:     true,
+	http.MethodPost:    true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+	http.MethodHead:    true,
+	http.MethodPatch:   true,
+	http.MethodTrace:   true,
+}
+
+var wildcardRe = regexp.MustCompile(`\{([a-zA-Z0-9_]+)(\.\.\.)?\}`)
+
+// openAPIPath converts a net/http path pattern to an OpenAPI path template.
+func openAPIPath(path string) string {
+	path = strings.ReplaceAll(path, "{$}", "")
+	return wildcardRe.ReplaceAllString(path, "{$1}")
+}
+
+// openAPIGen collects the component schemas while operations are generated.
+type openAPIGen struct {
+	schemas Map
+	names   map[reflect.Type]string // component names of the named structs
+}
+
+// operation describes a route as an OpenAPI operation.
+func (g *openAPIGen) operation(route RouteInfo) Map {
+	op := Map{}
+	if route.Name != "" {
+		op["operationId"] = route.Name
+	}
+
+	var fields []reflect.StructField
+	if route.Request != nil && route.Request.Kind() == reflect.Struct {
+		fields = structFields(route.Request)
+	}
+
+	var params []any
+	for _, match := range wildcardRe.FindAllStringSubmatch(route.Path, -1) {
+		schema := Map{"type": "string"}
+		if f, ok := fieldByTag(fields, "param", match[1]); ok {
+			schema = g.schema(f.Type)
+		}
+		params = append(params, Map{"name": match[1], "in": "path", "required": true, "schema": schema})
+	}
+	// Bind only binds the query parameters for GET, DELETE and HEAD
+	if route.Method == http.MethodGet || route.Method == http.MethodDelete || route.Method == http.MethodHead {
+		for _, f := range fields {
+			if name := f.Tag.Get("query"); name != "" {
+				params = append(params, Map{"name": name, "in": "query", "schema": g.schema(f.Type)})
+			}
+		}
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if body := g.requestBody(route.Method, fields); body != nil {
+		op["requestBody"] = body
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Map{"description": http.StatusText(status)}
+	if route.Response != nil {
+		success["content"] = Map{MIMEApplicationJSON: Map{"schema": g.schema(route.Response)}}
+	}
+
+	op["responses"] = Map{
+		strconv.Itoa(status): success,
+		"default": Map{
+			"description": "Error",
+			"content":     Map{MIMEApplicationJSON: Map{"schema": Map{"$ref": "#/components/schemas/HTTPError"}}},
+		},
+	}
+	return op
+}
+
+// requestBody describes the fields of the request type that are bound from the body.
+func (g *openAPIGen) requestBody(method string, fields []reflect.StructField) Map {
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodDelete {
+		return nil
+	}
+
+	content := Map{}
+	if props, required := g.properties(fields, "json", true); len(props) > 0 {
+		content[MIMEApplicationJSON] = Map{"schema": objectSchema(props, required)}
+	}
+	if props, _ := g.properties(fields, "form", false); len(props) > 0 {
+		content[MIMEApplicationForm] = Map{"schema": objectSchema(props, nil)}
+	}
+	if len(content) == 0 {
+		return nil
+	}
+	return Map{"content": content}
+}
+
+// properties returns the schemas of the fields named by tag. When untagged is true,
+// exported fields without the tag and without a param, query, header or form tag
+// are included under their Go name, as encoding/json does.
+func (g *openAPIGen) properties(fields []reflect.StructField, tag string, untagged bool) (Map, []string) {
+	props := Map{}
+	var required []string
+	for _, f := range fields {
+		name, opts, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			if !untagged || isBoundOutsideBody(f) {
+				continue
+			}
+			name = f.Name
+		}
+
+		props[name] = g.schema(f.Type)
+		if f.Type.Kind() != reflect.Pointer && !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return props, required
+}
+
+// schema returns the JSON schema of a Go type. Named structs are added to the
+// components and referenced.
+func (g *openAPIGen) schema(t reflect.Type) Map {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return Map{"type": "string", "format": "date-time"}
+	case reflect.TypeFor[multipart.FileHeader]():
+		return Map{"type": "string", "format": "binary"}
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) && t.Kind() != reflect.Slice {
+		return Map{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Map{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Map{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return Map{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return Map{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return Map{"type": "number", "format": "double"}
+	case reflect.String:
+		return Map{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Map{"type": "string", "format": "byte"}
+		}
+		return Map{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Map{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return Map{}
+	}
+}
+
+// structSchema returns the schema of a struct, referenced from the components when the struct is named.
+func (g *openAPIGen) structSchema(t reflect.Type) Map {
+	if t.Name() == "" {
+		props, required := g.properties(structFields(t), "json", true)
+		return objectSchema(props, required)
+	}
+
+	name, ok := g.schemaName(t)
+	ref := Map{"$ref": "#/components/schemas/" + name}
+	if ok {
+		return ref
+	}
+
+	// register a placeholder first so recursive types terminate
+	g.schemas[name] = Map{}
+	props, required := g.properties(structFields(t), "json", true)
+	g.schemas[name] = objectSchema(props, required)
+	return ref
+}
+
+var (
+	pkgPathRe    = regexp.MustCompile(`[^\[\],]*/`)
+	schemaNameRe = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// schemaName returns the component name of a named struct and whether it was
+// already given one. The name is the type name with the package paths of its
+// type arguments reduced to the package name and the characters OpenAPI does not
+// allow replaced, e.g. Page_users.User for Page[example.com/users.User]. A number
+// is appended when another type already has the name.
+func (g *openAPIGen) schemaName(t reflect.Type) (string, bool) {
+	if name, ok := g.names[t]; ok {
+		return name, true
+	}
+
+	base := pkgPathRe.ReplaceAllString(t.Name(), "")
+	base = strings.Trim(schemaNameRe.ReplaceAllString(base, "_"), "_")
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := g.schemas[name]; !taken {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	g.names[t] = name
+	return name, false
+}
+
+func objectSchema(props Map, required []string) Map {
+	schema := Map{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// structFields returns the exported fields of a struct, flattening embedded structs.
+func structFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+			fields = append(fields, structFields(f.Type)...)
+			continue
+		}
+		if f.IsExported() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func fieldByTag(fields []reflect.StructField, tag, name string) (reflect.StructField, bool) {
+	for _, f := range fields {
+		if f.Tag.Get(tag) == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// isBoundOutsideBody reports whether a field is bound from the path, query, header or form.
+func isBoundOutsideBody(f reflect.StructField) bool {
+	for _, tag := range []string{"param", "query", "header", "form"} {
+		if f.Tag.Get(tag) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package httpz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type openAPIAddress struct {
+	City string `json:"city"`
+}
+
+type openAPIUser struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	Email     *string          `json:"email"`
+	Tags      []string         `json:"tags,omitempty"`
+	Address   openAPIAddress   `json:"address"`
+	CreatedAt time.Time        `json:"created_at"`
+	Friends   []*openAPIUser   `json:"friends,omitempty"`
+	Meta      map[string]int32 `json:"meta,omitempty"`
+}
+
+type openAPIUpdateUser struct {
+	Org     string `param:"org"`
+	ID      int64  `param:"id"`
+	DryRun  bool   `query:"dry_run"`
+	TraceID string `header:"X-Trace-Id"`
+	Name    string `json:"name"`
+	Ignored string `json:"-"`
+}
+
+func TestServeMux_OpenAPIDocument(t *testing.T) {
+	mux := NewServeMux()
+	api := mux.Group("/api/")
+	HandleTyped(api, http.MethodPut, "/orgs/{org}/users/{id}", func(ctx context.Context, req *openAPIUpdateUser) (*openAPIUser, error) {
+		return &openAPIUser{}, nil
+	}).Name("users.update")
+	HandleTyped(api, http.MethodGet, "/orgs/{org}/users", func(ctx context.Context, req *openAPIUpdateUser) (*[]openAPIUser, error) {
+		return nil, nil
+	})
+	api.Get("/files/{path...}", listUsers)
+	api.Any("/any", listUsers)
+	api.Method("PROPFIND", "/any", listUsers)
+
+	data, err := json.Marshal(mux.OpenAPIDocument(OpenAPIInfo{Title: "Test", Version: "1.0.0"}))
+	assert.NoError(t, err)
+
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, "3.1.0", doc["openapi"])
+	assert.Equal(t, map[string]any{"title": "Test", "version": "1.0.0"}, doc["info"])
+
+	paths := doc["paths"].(map[string]any)
+	assert.Len(t, paths, 3)
+	assert.Contains(t, paths, "/api/files/{path}")
+	assert.NotContains(t, paths, "/api/any")
+
+	op := paths["/api/orgs/{org}/users/{id}"].(map[string]any)["put"].(map[string]any)
+	assert.Equal(t, "users.update", op["operationId"])
+
+	expected := `[
+		{"name":"org","in":"path","required":true,"schema":{"type":"string"}},
+		{"name":"id","in":"path","required":true,"schema":{"type":"integer","format":"int64"}}
+	]`
+	params, _ := json.Marshal(op["parameters"])
+	assert.JSONEq(t, expected, string(params))
+
+	// Bind reads the query parameters for GET and never reads the headers
+	list := paths["/api/orgs/{org}/users"].(map[string]any)["get"].(map[string]any)
+	params, _ = json.Marshal(list["parameters"])
+	assert.JSONEq(t, `[
+		{"name":"org","in":"path","required":true,"schema":{"type":"string"}},
+		{"name":"dry_run","in":"query","schema":{"type":"boolean"}}
+	]`, string(params))
+	assert.NotContains(t, list, "requestBody")
+
+	body, _ := json.Marshal(op["requestBody"])
+	assert.JSONEq(t, `{"content":{"application/json":{"schema":{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]}}}}`, string(body))
+
+	responses, _ := json.Marshal(op["responses"])
+	assert.JSONEq(t, `{
+		"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/openAPIUser"}}}},
+		"default":{"description":"Error","content":{"application/json":{"schema":{"$ref":"#/components/schemas/HTTPError"}}}}
+	}`, string(responses))
+
+	schemas, _ := json.Marshal(doc["components"].(map[string]any)["schemas"])
+	assert.JSONEq(t, `{
+		"HTTPError":{"type":"object","properties":{
+			"msg":{"type":"string"},
+			"code":{"type":"string"},
+			"fields":{"type":"array","items":{"$ref":"#/components/schemas/FieldError"}}
+		}},
+		"FieldError":{"type":"object","properties":{
+			"field":{"type":"string"},
+			"source":{"type":"string"},
+			"rule":{"type":"string"},
+			"message":{"type":"string"}
+		},"required":["field","message"]},
+		"openAPIAddress":{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]},
+		"openAPIUser":{"type":"object","properties":{
+			"id":{"type":"integer","format":"int64"},
+			"name":{"type":"string"},
+			"email":{"type":"string"},
+			"tags":{"type":"array","items":{"type":"string"}},
+			"address":{"$ref":"#/components/schemas/openAPIAddress"},
+			"created_at":{"type":"string","format":"date-time"},
+			"friends":{"type":"array","items":{"$ref":"#/components/schemas/openAPIUser"}},
+			"meta":{"type":"object","additionalProperties":{"type":"integer","format":"int32"}}
+		},"required":["id","name","address","created_at"]}
+	}`, string(schemas))
+}
+
+func TestServeMux_OpenAPI(t *testing.T) {
+	mux := NewServeMux()
+	mux.OpenAPI("/openapi.json", OpenAPIInfo{Title: "Test", Version: "1.0.0", Description: "test api"})
+	mux.Post("/users", listUsers)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, MIMEApplicationJSON, rec.Header().Get(HeaderContentType))
+
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "test api", doc["info"].(map[string]any)["description"])
+
+	paths := doc["paths"].(map[string]any)
+	assert.Len(t, paths, 1)
+	assert.Contains(t, paths["/users"], "post")
+}
+
+type openAPIPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestServeMux_OpenAPISchemaNames(t *testing.T) {
+	// same name as the package level type, so it needs another component name
+	type openAPIAddress struct {
+		Zip string `json:"zip"`
+	}
+
+	mux := NewServeMux()
+	HandleTyped(mux, http.MethodGet, "/users", func(ctx context.Context, req *struct{}) (*openAPIPage[openAPIUser], error) {
+		return nil, nil
+	})
+	HandleTyped(mux, http.MethodGet, "/address", func(ctx context.Context, req *struct{}) (*openAPIAddress, error) {
+		return nil, nil
+	})
+
+	data, err := json.Marshal(mux.OpenAPIDocument(OpenAPIInfo{Title: "Test", Version: "1.0.0"}))
+	assert.NoError(t, err)
+
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(data, &doc))
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	for name := range schemas {
+		assert.Regexp(t, `^[a-zA-Z0-9._-]+$`, name)
+	}
+	assert.Contains(t, schemas, "openAPIPage_httpz.openAPIUser")
+	assert.Contains(t, schemas["openAPIAddress"].(map[string]any)["properties"], "city")
+	assert.Contains(t, schemas["openAPIAddress2"].(map[string]any)["properties"], "zip")
+
+	paths := doc["paths"].(map[string]any)
+	users, _ := json.Marshal(paths["/users"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)["200"])
+	assert.Contains(t, string(users), `"#/components/schemas/openAPIPage_httpz.openAPIUser"`)
+	address, _ := json.Marshal(paths["/address"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)["200"])
+	assert.Contains(t, string(address), `"#/components/schemas/openAPIAddress2"`)
+}
@@ -0,0 +1,158 @@
+package httpz
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// TrailingSlashPolicy sets how a request path that matches no route, but would
+// match one with the trailing slash added or removed, is served.
+type TrailingSlashPolicy int
+
+const (
+	// TrailingSlashStrict leaves the request unmatched, as net/http does. It is the default.
+	TrailingSlashStrict TrailingSlashPolicy = iota
+	// TrailingSlashRedirect redirects to the path of the route, with
+	// 301 Moved Permanently for GET and HEAD requests and 308 Permanent Redirect otherwise.
+	TrailingSlashRedirect
+	// TrailingSlashMatch serves the route as if the request path was the one of the route.
+	TrailingSlashMatch
+)
+
+// canonicalPath returns the path of the request fixed to match a route of mux
+// according to the TrailingSlash and RedirectCase settings, and whether the
+// request should be redirected to it.
+func (sm *ServeMux) canonicalPath(r *http.Request, scope *ServeMux) (path string, redirect, ok bool) {
+	if scope.exactPattern(r) != "" {
+		return "", false, false
+	}
+
+	var candidates []string
+	if sm.TrailingSlash != TrailingSlashStrict && r.URL.Path != "/" {
+		if trimmed, found := strings.CutSuffix(r.URL.Path, "/"); found {
+			candidates = append(candidates, trimmed)
+		} else {
+			candidates = append(candidates, r.URL.Path+"/")
+		}
+	}
+
+	for _, path := range candidates {
+		if scope.matches(r, path) {
+			return path, sm.TrailingSlash == TrailingSlashRedirect, true
+		}
+	}
+
+	if !sm.RedirectCase {
+		return "", false, false
+	}
+
+	candidates = append([]string{r.URL.Path}, candidates...)
+	for _, route := range sm.registry().listPaths() {
+		if route.host != scope.host && route.host != "" {
+			continue
+		}
+		for _, candidate := range candidates {
+			if path, found := matchFold(route.path, candidate); found && scope.matches(r, path) {
+				return path, true, true
+			}
+		}
+	}
+	return "", false, false
+}
+
+// matches reports whether a route of the root or host group matches the request with the path.
+func (sm *ServeMux) matches(r *http.Request, path string) bool {
+	return sm.exactPattern(withPath(r, path)) != ""
+}
+
+var redirectHandlerType = reflect.TypeOf(http.RedirectHandler("/", http.StatusMovedPermanently))
+
+// exactPattern returns the pattern of the route of the root or host group
+// matching the request. It returns "" when no route matches, and when net/http
+// redirects the request to the path with a trailing slash of a subtree route,
+// such as /items to /items/.
+func (sm *ServeMux) exactPattern(r *http.Request) string {
+	h, pattern := sm.lookup(r)
+	if pattern == "" || reflect.TypeOf(h) != redirectHandlerType || strings.HasSuffix(r.URL.Path, "/") {
+		return pattern
+	}
+	if h2, pattern2 := sm.lookup(withPath(r, r.URL.Path+"/")); pattern2 == pattern && reflect.TypeOf(h2) != redirectHandlerType {
+		return ""
+	}
+	return pattern
+}
+
+// withPath returns a shallow copy of the request with the URL path replaced.
+func withPath(r *http.Request, path string) *http.Request {
+	u := *r.URL
+	u.Path, u.RawPath = path, ""
+
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = &u
+	return r2
+}
+
+// matchFold matches the request path against a route path ignoring the case
+// of its literal segments, and returns the request path with the literal
+// segments in the casing of the route.
+func matchFold(pattern, path string) (string, bool) {
+	var b strings.Builder
+	for {
+		if pattern == "" {
+			if path != "" {
+				return "", false
+			}
+			return b.String(), true
+		}
+		if pattern == "/" || pattern == "/{$}" {
+			// a trailing slash matches the subtree, {$} only the slash itself
+			if !strings.HasPrefix(path, "/") || pattern == "/{$}" && path != "/" {
+				return "", false
+			}
+			return b.String() + path, true
+		}
+		if !strings.HasPrefix(path, "/") {
+			return "", false
+		}
+
+		var want, got string
+		want, pattern = cutSegment(pattern[1:])
+		got, path = cutSegment(path[1:])
+		b.WriteByte('/')
+
+		switch {
+		case strings.HasSuffix(want, "...}"):
+			return b.String() + got + path, true
+		case strings.HasPrefix(want, "{") && strings.HasSuffix(want, "}"):
+			b.WriteString(got)
+		case strings.EqualFold(want, got):
+			b.WriteString(want)
+		default:
+			return "", false
+		}
+	}
+}
+
+// fixPath serves the request according to the path policies when its path
+// matches a route once fixed. It reports whether the request was served, and
+// otherwise returns the request to route.
+func (sm *ServeMux) fixPath(w http.ResponseWriter, r *http.Request, scope *ServeMux) (*http.Request, bool) {
+	path, redirect, ok := sm.canonicalPath(r, scope)
+	if !ok {
+		return r, false
+	}
+	if !redirect {
+		return withPath(r, path), false
+	}
+
+	code := http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		code = http.StatusMovedPermanently
+	}
+	u := url.URL{Path: path, RawQuery: r.URL.RawQuery}
+	http.Redirect(w, r, u.String(), code)
+	return r, true
+}
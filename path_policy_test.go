@@ -0,0 +1,112 @@
+package httpz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pathPolicyMux() *ServeMux {
+	mux := NewServeMux()
+	echo := func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, r.Pattern+" "+r.URL.Path)
+	}
+	mux.Get("/users", echo)
+	mux.Post("/users", echo)
+	mux.Get("/orgs/{org}/Members/", echo)
+	mux.Get("/Files/{path...}", echo)
+	mux.Post("/items/", echo)
+	return mux
+}
+
+func TestServeMux_TrailingSlash(t *testing.T) {
+	tests := []struct {
+		policy   TrailingSlashPolicy
+		method   string
+		path     string
+		code     int
+		location string
+		body     string
+	}{
+		{TrailingSlashStrict, http.MethodGet, "/users/", http.StatusNotFound, "", "404 page not found\n"},
+		{TrailingSlashRedirect, http.MethodGet, "/users/?page=2", http.StatusMovedPermanently, "/users?page=2", ""},
+		{TrailingSlashRedirect, http.MethodPost, "/users/", http.StatusPermanentRedirect, "/users", ""},
+		{TrailingSlashMatch, http.MethodGet, "/users/", http.StatusOK, "", "GET /users /users"},
+		{TrailingSlashMatch, http.MethodGet, "/users", http.StatusOK, "", "GET /users /users"},
+		{TrailingSlashMatch, http.MethodGet, "/missing/", http.StatusNotFound, "", "404 page not found\n"},
+		{TrailingSlashRedirect, http.MethodPost, "/items", http.StatusPermanentRedirect, "/items/", ""},
+		{TrailingSlashMatch, http.MethodPost, "/items", http.StatusOK, "", "POST /items/ /items/"},
+		{TrailingSlashMatch, http.MethodPost, "/items/1", http.StatusOK, "", "POST /items/ /items/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+tt.path, func(t *testing.T) {
+			mux := pathPolicyMux()
+			mux.TrailingSlash = tt.policy
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.location, rec.Header().Get(HeaderLocation))
+			if tt.body != "" {
+				assert.Equal(t, tt.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestServeMux_RedirectCase(t *testing.T) {
+	mux := pathPolicyMux()
+	mux.RedirectCase = true
+	mux.TrailingSlash = TrailingSlashRedirect
+
+	tests := []struct {
+		path     string
+		code     int
+		location string
+	}{
+		{"/USERS", http.StatusMovedPermanently, "/users"},
+		{"/Users/", http.StatusMovedPermanently, "/users"},
+		{"/ORGS/Acme/members/", http.StatusMovedPermanently, "/orgs/Acme/Members/"},
+		{"/orgs/Acme/members/Bob", http.StatusMovedPermanently, "/orgs/Acme/Members/Bob"},
+		{"/files/A/B.txt", http.StatusMovedPermanently, "/Files/A/B.txt"},
+		{"/users", http.StatusOK, ""},
+		{"/groups", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.location, rec.Header().Get(HeaderLocation))
+		})
+	}
+}
+
+func TestMatchFold(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    string
+		ok      bool
+	}{
+		{"/users", "/USERS", "/users", true},
+		{"/users/{id}", "/USERS/Bob", "/users/Bob", true},
+		{"/static/", "/STATIC/a/B", "/static/a/B", true},
+		{"/a/{$}", "/A/", "/a/", true},
+		{"/a/{$}", "/A/b", "", false},
+		{"/users", "/users/1", "", false},
+		{"/users/{id}", "/users", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := matchFold(tt.pattern, tt.path)
+		assert.Equal(t, tt.ok, ok, tt.pattern+" "+tt.path)
+		assert.Equal(t, tt.want, got, tt.pattern+" "+tt.path)
+	}
+}
@@ -0,0 +1,78 @@
+package httpz
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// ProblemErrHandlerFunc is a request-aware error handling function sending
+// RFC 9457 Problem Details as application/problem+json.
+//
+// The status code of a *HTTPError gives the status member and the title, its
+// message the detail member when it differs from the title, and its Type,
+// Instance and Extensions fields the other members. Code and Fields are sent
+// as the code and fields extension members. Any other error is logged
+// and sent as a 500 Internal Server Error problem, without its message.
+//
+//	mux.RequestErrHandlerFunc = httpz.ProblemErrHandlerFunc
+//
+//	return httpz.NewHTTPError(http.StatusForbidden, "Your balance is 30, but that costs 50.").
+//		SetType("https://example.com/probs/out-of-credit").
+//		SetExtension("balance", 30)
+func ProblemErrHandlerFunc(err error, w http.ResponseWriter, r *http.Request) {
+	he, ok := err.(*HTTPError)
+	if !ok {
+		slog.Error(err.Error(), requestAttrs(w, r)...)
+		he = NewHTTPError(helper(http.StatusInternalServerError))
+	} else if he.StatusCode >= http.StatusInternalServerError && he.Internal != nil {
+		slog.Error(he.Error(), requestAttrs(w, r)...)
+	}
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(he.StatusCode)
+		return
+	}
+
+	w.Header().Set(HeaderContentType, MIMEApplicationProblemJSON)
+	w.WriteHeader(he.StatusCode)
+	json.NewEncoder(w).Encode(problem(he))
+}
+
+// problem returns the Problem Details members describing the HTTPError.
+// Extension members do not override the standard members.
+func problem(he *HTTPError) Map {
+	p := make(Map, len(he.Extensions)+7)
+	if he.Code != "" {
+		p["code"] = he.Code
+	}
+	if len(he.Fields) > 0 {
+		p["fields"] = he.Fields
+	}
+	for k, v := range he.Extensions {
+		p[k] = v
+	}
+
+	title := http.StatusText(he.StatusCode)
+	if title == "" {
+		title = he.Msg
+	}
+
+	p["type"] = "about:blank"
+	if he.Type != "" {
+		p["type"] = he.Type
+	}
+	p["status"] = he.StatusCode
+	p["title"] = title
+	if he.Msg != "" && he.Msg != title {
+		p["detail"] = he.Msg
+	} else {
+		delete(p, "detail")
+	}
+	if he.Instance != "" {
+		p["instance"] = he.Instance
+	} else {
+		delete(p, "instance")
+	}
+	return p
+}
@@ -0,0 +1,80 @@
+package httpz
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemErrHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+		body string
+	}{
+		{
+			"status only",
+			NewHTTPError(helper(http.StatusNotFound)),
+			http.StatusNotFound,
+			`{"type":"about:blank","status":404,"title":"Not Found"}`,
+		},
+		{
+			"detail",
+			NewHTTPError(http.StatusUnprocessableEntity, "name is required"),
+			http.StatusUnprocessableEntity,
+			`{"type":"about:blank","status":422,"title":"Unprocessable Entity","detail":"name is required"}`,
+		},
+		{
+			"members and extensions",
+			NewHTTPError(http.StatusForbidden, "Your balance is 30, but that costs 50.").
+				SetType("https://example.com/probs/out-of-credit").
+				SetInstance("/account/12345/msgs/abc").
+				SetExtension("balance", 30).
+				SetExtension("status", 200),
+			http.StatusForbidden,
+			`{"type":"https://example.com/probs/out-of-credit","status":403,"title":"Forbidden",
+				"detail":"Your balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc","balance":30}`,
+		},
+		{
+			"other error",
+			errors.New("boom"),
+			http.StatusInternalServerError,
+			`{"type":"about:blank","status":500,"title":"Internal Server Error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ProblemErrHandlerFunc(tt.err, rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(HeaderContentType))
+			assert.JSONEq(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestProblemErrHandlerFunc_Mux(t *testing.T) {
+	mux := NewServeMux()
+	mux.RequestErrHandlerFunc = ProblemErrHandlerFunc
+	mux.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return NewHTTPError(http.StatusNotFound, "user not found").SetInstance(r.URL.Path)
+	})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"type":"about:blank","status":404,"title":"Not Found","detail":"user not found","instance":"/users/1"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/users/1", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
@@ -0,0 +1,55 @@
+package httpz
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is the internal error of the *HTTPError produced when a handler
+// panics and RecoverPanics is enabled.
+type PanicError struct {
+	Value any    // Value passed to panic
+	Stack []byte // Stack trace of the goroutine at the time of the panic
+}
+
+// Error returns the panic value and the stack trace.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the panic value when it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// call runs h and, when RecoverPanics is enabled on the mux or one of its parents,
+// turns a panic into a 500 *HTTPError whose internal error is a *PanicError.
+// http.ErrAbortHandler is re-panicked so net/http aborts the response.
+func (sm *ServeMux) call(h HandlerFunc, w http.ResponseWriter, r *http.Request) (err error) {
+	if !sm.recoversPanics() {
+		return h(w, r)
+	}
+
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			if rvr == http.ErrAbortHandler {
+				panic(rvr)
+			}
+			err = NewHTTPError(helper(http.StatusInternalServerError)).SetInternal(&PanicError{Value: rvr, Stack: debug.Stack()})
+		}
+	}()
+
+	return h(w, r)
+}
+
+// recoversPanics reports whether RecoverPanics is enabled on the mux or one of its parents.
+func (sm *ServeMux) recoversPanics() bool {
+	for mux := sm; mux != nil; mux = mux.parent {
+		if mux.RecoverPanics {
+			return true
+		}
+	}
+	return false
+}
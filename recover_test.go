@@ -0,0 +1,83 @@
+package httpz
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMux_RecoverPanics(t *testing.T) {
+	mux := NewServeMux()
+	mux.RecoverPanics = true
+
+	var got error
+	mux.RequestErrHandlerFunc = func(err error, w http.ResponseWriter, r *http.Request) {
+		got = err
+		DefaultRequestErrHandlerFunc(err, w, r)
+	}
+
+	api := mux.Group("/api/")
+	api.Get("/panic", func(w http.ResponseWriter, r *http.Request) error {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/panic", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"msg":"Internal Server Error"}`, rec.Body.String())
+
+	var he *HTTPError
+	assert.ErrorAs(t, got, &he)
+	assert.Equal(t, http.StatusInternalServerError, he.StatusCode)
+
+	var pe *PanicError
+	assert.ErrorAs(t, got, &pe)
+	assert.Equal(t, "boom", pe.Value)
+	assert.Contains(t, string(pe.Stack), "recover_test.go")
+}
+
+func TestServeMux_RecoverPanicsErrorValue(t *testing.T) {
+	boom := errors.New("boom")
+
+	mux := NewServeMux()
+	mux.RecoverPanics = true
+
+	var got error
+	mux.RequestErrHandlerFunc = func(err error, w http.ResponseWriter, r *http.Request) {
+		got = err
+	}
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) error {
+		panic(boom)
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.ErrorIs(t, got, boom)
+}
+
+func TestServeMux_RecoverPanicsDisabled(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) error {
+		panic("boom")
+	})
+
+	assert.PanicsWithValue(t, "boom", func() {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestServeMux_RecoverPanicsAbortHandler(t *testing.T) {
+	mux := NewServeMux()
+	mux.RecoverPanics = true
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) error {
+		panic(http.ErrAbortHandler)
+	})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,347 @@
+package httpz
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// RouteInfo describes a route registered on a ServeMux.
+type RouteInfo struct {
+	Name        string                // Name given with Route.Name, empty if unnamed
+	Method      string                // HTTP method, empty if the route matches any method
+	Host        string                // Host pattern, empty if the route matches any host
+	Path        string                // Full path including every group prefix, e.g. /v1/users/{id}
+	Pattern     string                // Full pattern as understood by net/http, e.g. GET /v1/users/{id}
+	Handler     string                // Name of the handler function
+	Middlewares []RouteMiddlewareFunc // Route-specific middleware attached to the route
+	Request     reflect.Type          // Request type of a route registered with HandleTyped
+	Response    reflect.Type          // Response type of a route registered with HandleTyped
+	Status      int                   // Success status of a route registered with HandleTyped
+}
+
+// WalkFunc is the type of the function called by Walk for each registered route.
+// Returning a non-nil error stops the walk and Walk returns that error.
+type WalkFunc func(route RouteInfo) error
+
+// Route is a handle to a registered route, returned by the registration methods of ServeMux.
+type Route struct {
+	reg *routeRegistry
+	idx int
+}
+
+// Name assigns a name to the route so its URL can be built with ServeMux.URL.
+// It panics if the name is empty or already used by another route.
+func (rt *Route) Name(name string) *Route {
+	rt.reg.setName(rt.idx, name)
+	return rt
+}
+
+// Info returns the description of the route.
+func (rt *Route) Info() RouteInfo {
+	rt.reg.mu.RLock()
+	defer rt.reg.mu.RUnlock()
+	return rt.reg.routes[rt.idx]
+}
+
+// routeRegistry records every route registered through a root ServeMux and its groups.
+type routeRegistry struct {
+	mu        sync.RWMutex
+	routes    []RouteInfo
+	names     map[string]int // route name -> index in routes
+	groups    []*ServeMux
+	hosts     []*ServeMux
+	versions  []*Versions
+	errs      []error // registration errors collected after CollectErrors
+	errMaps   []errorMapper
+	methods   []string    // sorted methods of the routes, HEAD included with GET
+	paths     []routePath // distinct host and path pairs of the routes
+	pathSet   map[routePath]bool
+	hooks     atomic.Pointer[hooks]
+	versioned atomic.Bool // set once Versions is called anywhere in the tree
+	hosted    atomic.Bool // set once Host is called on the root
+	collect   atomic.Bool // set by CollectErrors
+}
+
+func (rr *routeRegistry) addGroup(g *ServeMux) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.groups = append(rr.groups, g)
+}
+
+func (rr *routeRegistry) addHost(hg *ServeMux) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.hosts = append(rr.hosts, hg)
+	rr.hosted.Store(true)
+}
+
+func (rr *routeRegistry) listHosts() []*ServeMux {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.hosts
+}
+
+func (rr *routeRegistry) addVersions(v *Versions) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.versions = append(rr.versions, v)
+	rr.versioned.Store(true)
+}
+
+func (rr *routeRegistry) listVersions() []*Versions {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.versions
+}
+
+func (rr *routeRegistry) listGroups() []*ServeMux {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.groups
+}
+
+func (rr *routeRegistry) add(info RouteInfo) *Route {
+	rr.mu.Lock()
+	rr.routes = append(rr.routes, info)
+	route := &Route{reg: rr, idx: len(rr.routes) - 1}
+	if p := (routePath{host: info.Host, path: info.Path}); !rr.pathSet[p] {
+		if rr.pathSet == nil {
+			rr.pathSet = map[routePath]bool{}
+		}
+		rr.pathSet[p] = true
+		rr.paths = append(rr.paths, p)
+	}
+	if info.Method != "" {
+		rr.addMethod(info.Method)
+		if info.Method == http.MethodGet {
+			rr.addMethod(http.MethodHead)
+		}
+	}
+	rr.mu.Unlock()
+
+	if hk := rr.hooks.Load(); hk != nil {
+		for _, fn := range hk.route {
+			fn(info)
+		}
+	}
+	return route
+}
+
+// addMethod adds the method to the sorted set of route methods, rr.mu must be held.
+// The set is copied, so the slices returned by listMethods are never modified.
+func (rr *routeRegistry) addMethod(method string) {
+	if i, found := slices.BinarySearch(rr.methods, method); !found {
+		rr.methods = slices.Insert(slices.Clip(rr.methods), i, method)
+	}
+}
+
+// routePath is the host and the path of registered routes, whatever their method.
+type routePath struct {
+	host string
+	path string
+}
+
+// listPaths returns the distinct host and path pairs of the registered routes.
+func (rr *routeRegistry) listPaths() []routePath {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.paths
+}
+
+// listMethods returns the sorted set of methods used by registered routes.
+func (rr *routeRegistry) listMethods() []string {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.methods
+}
+
+func (rr *routeRegistry) setName(idx int, name string) {
+	if name == "" {
+		rr.fail(rr.routes[idx].Pattern, "route name must not be empty")
+		return
+	}
+
+	rr.mu.Lock()
+	if i, ok := rr.names[name]; ok && i != idx {
+		msg := fmt.Sprintf("route name %q is already used by %q", name, rr.routes[i].Pattern)
+		pattern := rr.routes[idx].Pattern
+		rr.mu.Unlock()
+		rr.fail(pattern, msg)
+		return
+	}
+	defer rr.mu.Unlock()
+	if rr.names == nil {
+		rr.names = make(map[string]int)
+	}
+	if old := rr.routes[idx].Name; old != "" {
+		delete(rr.names, old)
+	}
+	rr.names[name] = idx
+	rr.routes[idx].Name = name
+}
+
+func (rr *routeRegistry) lookup(name string) (RouteInfo, bool) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	idx, ok := rr.names[name]
+	if !ok {
+		return RouteInfo{}, false
+	}
+	return rr.routes[idx], true
+}
+
+func (rr *routeRegistry) list() []RouteInfo {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	routes := make([]RouteInfo, len(rr.routes))
+	copy(routes, rr.routes)
+	return routes
+}
+
+// Routes returns every route registered on the mux and its groups, in registration order.
+// Calling Routes on a group returns the routes of the whole tree.
+func (sm *ServeMux) Routes() []RouteInfo {
+	return sm.registry().list()
+}
+
+// Walk calls fn for every registered route in registration order.
+func (sm *ServeMux) Walk(fn WalkFunc) error {
+	for _, route := range sm.Routes() {
+		if err := fn(route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// URL builds the path of the route registered with the given name.
+// params are key/value pairs used to fill the {name} and {name...} wildcards of the pattern,
+// for example:
+//
+//	mux.Get("/users/{id}", h).Name("user.show")
+//	path, err := mux.URL("user.show", "id", "42") // "/users/42"
+//
+// Values are escaped, a {name...} value keeps its slashes and {$} is dropped.
+func (sm *ServeMux) URL(name string, params ...string) (string, error) {
+	route, ok := sm.registry().lookup(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrRouteNameNotFound, name)
+	}
+
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("httpz: odd number of params for route %q", name)
+	}
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	return buildPath(route.Path, values)
+}
+
+// buildPath replaces the wildcards of a pattern path with the given values.
+func buildPath(path string, values map[string]string) (string, error) {
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			sb.WriteString(path)
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("httpz: bad wildcard in path %q", path)
+		}
+		end += start
+
+		sb.WriteString(path[:start])
+		wildcard := path[start+1 : end]
+		path = path[end+1:]
+
+		if wildcard == "$" {
+			continue
+		}
+
+		key, multi := strings.CutSuffix(wildcard, "...")
+		value, ok := values[key]
+		if !ok {
+			return "", fmt.Errorf("httpz: missing value for param %q", key)
+		}
+
+		if !multi {
+			sb.WriteString(url.PathEscape(value))
+			continue
+		}
+		segments := strings.Split(value, "/")
+		for i, seg := range segments {
+			segments[i] = url.PathEscape(seg)
+		}
+		sb.WriteString(strings.Join(segments, "/"))
+	}
+
+	return sb.String(), nil
+}
+
+// registry returns the route registry shared by the whole mux tree.
+func (sm *ServeMux) registry() *routeRegistry {
+	if sm.root != nil {
+		return sm.root.registry()
+	}
+	sm.regOnce.Do(func() {
+		if sm.reg == nil {
+			sm.reg = &routeRegistry{}
+		}
+		sm.ServeMux.Handle(catchAllPattern, http.HandlerFunc(sm.serveUnmatched))
+	})
+	return sm.reg
+}
+
+// routeInfo describes the route registered with the pattern relative to the mux.
+func (sm *ServeMux) routeInfo(pattern string, h any, m []RouteMiddlewareFunc) RouteInfo {
+	method, host, path := parsePattern(pattern)
+	if sm.hostGroup != nil {
+		host = sm.hostGroup.host
+	}
+
+	return RouteInfo{
+		Method:      method,
+		Host:        host,
+		Path:        sm.prefix + path,
+		Pattern:     sm.routePattern(pattern),
+		Handler:     handlerName(h),
+		Middlewares: m,
+	}
+}
+
+// parsePattern splits a net/http pattern into its method, host and path parts.
+func parsePattern(pattern string) (method, host, path string) {
+	rest := strings.TrimSpace(pattern)
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		method, rest = rest[:i], strings.TrimLeft(rest[i+1:], " \t")
+	}
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		return method, rest[:i], rest[i:]
+	}
+	return method, rest, ""
+}
+
+// handlerName returns the name of a handler function, or its type for other handlers.
+func handlerName(h any) string {
+	if h == nil {
+		return ""
+	}
+	v := reflect.ValueOf(h)
+	if v.Kind() == reflect.Func {
+		if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return v.Type().String()
+}
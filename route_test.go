@@ -0,0 +1,113 @@
+package httpz
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func listUsers(w http.ResponseWriter, r *http.Request) error { return nil }
+
+func TestServeMux_Routes(t *testing.T) {
+	mux := NewServeMux()
+	mw := func(next HandlerFunc) HandlerFunc { return next }
+
+	mux.Get("/users", listUsers, mw)
+	mux.HandleFunc("/health", listUsers)
+
+	v1 := mux.Group("/v1/")
+	auth := v1.Group("/auth/")
+	auth.Post("/login/{provider}", listUsers)
+
+	routes := mux.Routes()
+	assert.Len(t, routes, 3)
+
+	assert.Equal(t, http.MethodGet, routes[0].Method)
+	assert.Equal(t, "/users", routes[0].Path)
+	assert.Equal(t, "GET /users", routes[0].Pattern)
+	assert.True(t, strings.HasSuffix(routes[0].Handler, ".listUsers"))
+	assert.Len(t, routes[0].Middlewares, 1)
+
+	assert.Equal(t, "", routes[1].Method)
+	assert.Equal(t, "/health", routes[1].Pattern)
+
+	assert.Equal(t, http.MethodPost, routes[2].Method)
+	assert.Equal(t, "/v1/auth/login/{provider}", routes[2].Path)
+	assert.Equal(t, "POST /v1/auth/login/{provider}", routes[2].Pattern)
+
+	assert.Equal(t, routes, auth.Routes())
+}
+
+func TestServeMux_Walk(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/a", listUsers)
+	mux.Handle("/static/", http.NotFoundHandler())
+	mux.Delete("/b", listUsers)
+
+	var patterns []string
+	err := mux.Walk(func(route RouteInfo) error {
+		patterns = append(patterns, route.Pattern)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET /a", "/static/", "DELETE /b"}, patterns)
+
+	stop := errors.New("stop")
+	count := 0
+	err = mux.Walk(func(route RouteInfo) error {
+		count++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestServeMux_URL(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/users/{id}", listUsers).Name("user.show")
+	mux.Get("/{$}", listUsers).Name("home")
+
+	api := mux.Group("/api/")
+	files := api.Get("/files/{path...}", listUsers).Name("file")
+	assert.Equal(t, "file", files.Info().Name)
+
+	u, err := mux.URL("user.show", "id", "42")
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/42", u)
+
+	u, err = mux.URL("user.show", "id", "a b/c")
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/a%20b%2Fc", u)
+
+	u, err = mux.URL("home")
+	assert.NoError(t, err)
+	assert.Equal(t, "/", u)
+
+	u, err = api.URL("file", "path", "docs/read me.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/files/docs/read%20me.txt", u)
+
+	_, err = mux.URL("user.show")
+	assert.EqualError(t, err, `httpz: missing value for param "id"`)
+
+	_, err = mux.URL("user.show", "id")
+	assert.Error(t, err)
+
+	_, err = mux.URL("unknown")
+	assert.ErrorIs(t, err, ErrRouteNameNotFound)
+}
+
+func TestRoute_NameConflict(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/a", listUsers).Name("a")
+
+	assert.Panics(t, func() {
+		mux.Get("/b", listUsers).Name("a")
+	})
+	assert.Panics(t, func() {
+		mux.Get("/c", listUsers).Name("")
+	})
+}
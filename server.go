@@ -0,0 +1,227 @@
+package httpz
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// DefaultShutdownTimeout is the time a Server waits for in-flight requests
+// to finish during a graceful shutdown.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Server runs an http.Handler until the context is canceled or the process
+// receives SIGINT or SIGTERM, then shuts it down gracefully.
+type Server struct {
+	// Handler serves the requests.
+	Handler http.Handler
+	// HTTPServer is used as a template for timeouts and other settings, optional.
+	// Its Addr and Handler are ignored.
+	HTTPServer *http.Server
+	// TLSConfig is the TLS configuration used by StartTLS, optional.
+	TLSConfig *tls.Config
+	// Certificates provides the certificates to StartTLS and is watched for
+	// changes while the server runs, optional.
+	Certificates *CertReloader
+	// H2C enables HTTP/2 without TLS for Start, with prior knowledge or an
+	// Upgrade: h2c request, for deployments behind a proxy that terminates TLS.
+	H2C bool
+	// Network is the listener network: tcp, tcp4, tcp6 or unix. Default is tcp.
+	Network string
+	// ShutdownTimeout bounds the graceful shutdown. Default is DefaultShutdownTimeout.
+	ShutdownTimeout time.Duration
+	// Signals that trigger the graceful shutdown. Default is SIGINT and SIGTERM.
+	Signals []os.Signal
+
+	addr atomic.Pointer[net.Addr]
+}
+
+// NewServer returns a new Server for the handler with default settings.
+func NewServer(h http.Handler) *Server {
+	return &Server{Handler: h}
+}
+
+// Start listens on addr and serves HTTP until ctx is canceled or a shutdown
+// signal is received. It returns nil after a graceful shutdown.
+func (s *Server) Start(ctx context.Context, addr string) error {
+	return s.start(ctx, addr, nil)
+}
+
+// StartTLS listens on addr and serves HTTPS like Start.
+// cert and key are either a file path, PEM encoded content as a string, or
+// PEM encoded content as []byte. They may both be nil when Certificates or
+// TLSConfig already provide the certificates.
+func (s *Server) StartTLS(ctx context.Context, addr string, cert, key any) error {
+	cfg := new(tls.Config)
+	if s.TLSConfig != nil {
+		cfg = s.TLSConfig.Clone()
+	}
+
+	if cert != nil || key != nil {
+		certPEM, err := filepathOrContent(cert)
+		if err != nil {
+			return err
+		}
+		keyPEM, err := filepathOrContent(key)
+		if err != nil {
+			return err
+		}
+
+		pair, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return err
+		}
+		cfg.Certificates = append(cfg.Certificates, pair)
+	}
+
+	if s.Certificates != nil {
+		cfg.GetCertificate = s.Certificates.GetCertificate
+	}
+
+	if len(cfg.NextProtos) == 0 {
+		cfg.NextProtos = []string{"h2", "http/1.1"}
+	}
+
+	return s.start(ctx, addr, cfg)
+}
+
+// Addr returns the address the server listens on, or nil before it listens.
+func (s *Server) Addr() net.Addr {
+	if addr := s.addr.Load(); addr != nil {
+		return *addr
+	}
+	return nil
+}
+
+func (s *Server) start(ctx context.Context, addr string, cfg *tls.Config) error {
+	network := s.Network
+	if network == "" {
+		network = "tcp"
+	}
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+	default:
+		return ErrInvalidListenerNetwork
+	}
+
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		return err
+	}
+	if cfg != nil {
+		ln = tls.NewListener(ln, cfg)
+	}
+
+	lnAddr := ln.Addr()
+	s.addr.Store(&lnAddr)
+
+	return s.serve(ctx, ln, cfg)
+}
+
+// serve serves on ln until ctx is done or a signal is received, then shuts down.
+func (s *Server) serve(ctx context.Context, ln net.Listener, cfg *tls.Config) error {
+	signals := s.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	ctx, stop := signal.NotifyContext(ctx, signals...)
+	defer stop()
+
+	srv := new(http.Server)
+	if s.HTTPServer != nil {
+		srv = &http.Server{
+			ReadTimeout:       s.HTTPServer.ReadTimeout,
+			ReadHeaderTimeout: s.HTTPServer.ReadHeaderTimeout,
+			WriteTimeout:      s.HTTPServer.WriteTimeout,
+			IdleTimeout:       s.HTTPServer.IdleTimeout,
+			MaxHeaderBytes:    s.HTTPServer.MaxHeaderBytes,
+			ErrorLog:          s.HTTPServer.ErrorLog,
+			BaseContext:       s.HTTPServer.BaseContext,
+			ConnContext:       s.HTTPServer.ConnContext,
+			ConnState:         s.HTTPServer.ConnState,
+		}
+	}
+	srv.Handler = s.Handler
+	srv.TLSConfig = cfg
+
+	if cfg == nil && s.H2C {
+		// ConfigureServer lets Shutdown close the hijacked HTTP/2 connections gracefully
+		h2s := new(http2.Server)
+		if err := http2.ConfigureServer(srv, h2s); err != nil {
+			ln.Close()
+			return err
+		}
+		srv.Handler = h2c.NewHandler(s.Handler, h2s)
+	}
+
+	if cfg != nil && s.Certificates != nil {
+		go s.Certificates.Watch(ctx)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	timeout := s.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return err
+	}
+
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// filepathOrContent returns the PEM content of a cert or key given as a file path,
+// PEM content as a string, or PEM content as []byte.
+func filepathOrContent(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		if strings.HasPrefix(strings.TrimSpace(v), "-----BEGIN") {
+			return []byte(v), nil
+		}
+		return os.ReadFile(v)
+	case []byte:
+		return v, nil
+	default:
+		return nil, ErrInvalidCertOrKeyType
+	}
+}
+
+// Start serves the mux on addr until SIGINT or SIGTERM, then shuts down gracefully.
+// See Server for more options.
+func (sm *ServeMux) Start(addr string) error {
+	return NewServer(sm).Start(context.Background(), addr)
+}
+
+// StartTLS serves the mux over HTTPS on addr until SIGINT or SIGTERM, then shuts
+// down gracefully. See Server.StartTLS for the accepted cert and key types.
+func (sm *ServeMux) StartTLS(addr string, cert, key any) error {
+	return NewServer(sm).StartTLS(context.Background(), addr, cert, key)
+}
@@ -0,0 +1,218 @@
+package httpz
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+)
+
+// startServer runs start in the background and waits until the server listens.
+func startServer(t *testing.T, s *Server, start func(ctx context.Context) error) (net.Addr, context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- start(ctx)
+	}()
+
+	assert.Eventually(t, func() bool { return s.Addr() != nil }, time.Second, 5*time.Millisecond)
+	return s.Addr(), cancel, errCh
+}
+
+func helloMux() *ServeMux {
+	mux := NewServeMux()
+	mux.Get("/hello", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, "hello")
+	})
+	return mux
+}
+
+func TestServer_Start(t *testing.T) {
+	s := NewServer(helloMux())
+	addr, cancel, errCh := startServer(t, s, func(ctx context.Context) error {
+		return s.Start(ctx, "127.0.0.1:0")
+	})
+
+	resp, err := http.Get("http://" + addr.String() + "/hello")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "hello", string(body))
+	}
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	started := make(chan struct{})
+	mux := NewServeMux()
+	mux.Get("/slow", func(w http.ResponseWriter, r *http.Request) error {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		return String(w, http.StatusOK, "done")
+	})
+
+	s := NewServer(mux)
+	addr, cancel, errCh := startServer(t, s, func(ctx context.Context) error {
+		return s.Start(ctx, "127.0.0.1:0")
+	})
+
+	respCh := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr.String() + "/slow")
+		if err != nil {
+			respCh <- err.Error()
+			return
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		respCh <- string(body)
+	}()
+
+	<-started
+	cancel()
+	assert.NoError(t, <-errCh)
+	assert.Equal(t, "done", <-respCh)
+}
+
+func TestServer_Unix(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "httpz.sock")
+	s := NewServer(helloMux())
+	s.Network = "unix"
+	_, cancel, errCh := startServer(t, s, func(ctx context.Context) error {
+		return s.Start(ctx, sock)
+	})
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", sock)
+		},
+	}}
+	resp, err := client.Get("http://unix/hello")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "hello", string(body))
+	}
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
+
+func TestServer_InvalidNetwork(t *testing.T) {
+	s := NewServer(helloMux())
+	s.Network = "udp"
+	assert.ErrorIs(t, s.Start(context.Background(), "127.0.0.1:0"), ErrInvalidListenerNetwork)
+}
+
+func TestServer_StartTLS(t *testing.T) {
+	certPEM, err := os.ReadFile("testdata/cert.pem")
+	assert.NoError(t, err)
+	keyPEM, err := os.ReadFile("testdata/key.pem")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		cert any
+		key  any
+	}{
+		{"file", "testdata/cert.pem", "testdata/key.pem"},
+		{"string", string(certPEM), string(keyPEM)},
+		{"bytes", certPEM, keyPEM},
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(helloMux())
+			addr, cancel, errCh := startServer(t, s, func(ctx context.Context) error {
+				return s.StartTLS(ctx, "127.0.0.1:0", tt.cert, tt.key)
+			})
+
+			resp, err := client.Get("https://" + addr.String() + "/hello")
+			if assert.NoError(t, err) {
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				assert.Equal(t, "hello", string(body))
+			}
+
+			cancel()
+			assert.NoError(t, <-errCh)
+		})
+	}
+}
+
+func TestServer_StartTLS_InvalidType(t *testing.T) {
+	s := NewServer(helloMux())
+	err := s.StartTLS(context.Background(), "127.0.0.1:0", 1, "testdata/key.pem")
+	assert.ErrorIs(t, err, ErrInvalidCertOrKeyType)
+
+	err = s.StartTLS(context.Background(), "127.0.0.1:0", "testdata/cert.pem", nil)
+	assert.ErrorIs(t, err, ErrInvalidCertOrKeyType)
+}
+
+func TestServer_H2C(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/proto", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, r.Proto)
+	})
+
+	s := NewServer(mux)
+	s.H2C = true
+	addr, cancel, errCh := startServer(t, s, func(ctx context.Context) error {
+		return s.Start(ctx, "127.0.0.1:0")
+	})
+
+	// prior knowledge
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, network, addr)
+		},
+	}}
+	resp, err := client.Get("http://" + addr.String() + "/proto")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "HTTP/2.0", string(body))
+	}
+
+	// upgrade from HTTP/1.1
+	conn, err := net.Dial("tcp", addr.String())
+	if assert.NoError(t, err) {
+		_, err = io.WriteString(conn, "GET /proto HTTP/1.1\r\nHost: localhost\r\n"+
+			"Connection: Upgrade, HTTP2-Settings\r\nUpgrade: h2c\r\nHTTP2-Settings: AAMAAABkAAQAAP__\r\n\r\n")
+		assert.NoError(t, err)
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+			assert.Equal(t, "h2c", resp.Header.Get("Upgrade"))
+		}
+		conn.Close()
+	}
+
+	// plain HTTP/1.1 keeps working
+	resp, err = http.Get("http://" + addr.String() + "/proto")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "HTTP/1.1", string(body))
+	}
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
@@ -0,0 +1,120 @@
+package httpz
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TypedFunc defines the function signature for a typed handler.
+// It receives the bound request and returns the response to encode.
+type TypedFunc[Req, Resp any] func(ctx context.Context, req *Req) (*Resp, error)
+
+// TypedOption configures a typed handler.
+type TypedOption func(*typedConfig)
+
+type typedConfig struct {
+	status int
+}
+
+func newTypedConfig(opts []TypedOption) typedConfig {
+	cfg := typedConfig{status: http.StatusOK}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// TypedStatus sets the status code sent with a successful response, http.StatusOK by default.
+func TypedStatus(code int) TypedOption {
+	return func(c *typedConfig) {
+		c.status = code
+	}
+}
+
+// Typed converts a TypedFunc to a HandlerFunc.
+// The request is bound with Bind, fn is called with the request context, and the
+// response is sent as JSON, or as XML when the Accept header prefers it.
+// A nil response sends the status code without a body, errors go through the
+// centralized error handler.
+//
+//	mux.Post("/users", httpz.Typed(createUser, httpz.TypedStatus(http.StatusCreated)))
+func Typed[Req, Resp any](fn TypedFunc[Req, Resp], opts ...TypedOption) HandlerFunc {
+	cfg := newTypedConfig(opts)
+
+	return func(w http.ResponseWriter, r *http.Request) error {
+		req := new(Req)
+		if err := Bind(r, req); err != nil {
+			return err
+		}
+
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		if resp == nil {
+			w.WriteHeader(cfg.status)
+			return nil
+		}
+
+		rw := NewHelperRW(w)
+		if negotiate(r, MIMEApplicationJSON, MIMEApplicationXML) == MIMEApplicationXML {
+			return rw.XML(cfg.status, resp, "")
+		}
+		return rw.JSON(cfg.status, resp)
+	}
+}
+
+// HandleTyped registers fn for the method and path on mux like Typed, and records
+// the request and response types and the success status in the RouteInfo of the route.
+func HandleTyped[Req, Resp any](mux *ServeMux, method, path string, fn TypedFunc[Req, Resp], opts ...TypedOption) *Route {
+	h := Typed(fn, opts...)
+	info := mux.routeInfo(method+" "+path, h, nil)
+	info.Request, info.Response, info.Status = reflect.TypeFor[Req](), reflect.TypeFor[Resp](), newTypedConfig(opts).status
+	return mux.method(method, path, h, nil, info)
+}
+
+// negotiate returns the offer preferred by the Accept header of the request,
+// or the first offer when none is acceptable or the header is missing.
+func negotiate(r *http.Request, offers ...string) string {
+	accept := r.Header.Get(HeaderAccept)
+	if accept == "" {
+		return offers[0]
+	}
+
+	best, bestQ := offers[0], 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+
+		for _, offer := range offers {
+			if q > bestQ && matchMediaType(mediaType, offer) {
+				best, bestQ = offer, q
+			}
+		}
+	}
+	return best
+}
+
+// matchMediaType reports whether the media range from an Accept header matches mediaType.
+func matchMediaType(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	if typ, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(mediaType, typ+"/")
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package httpz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type createUserReq struct {
+	Org  string `param:"org"`
+	Name string `json:"name"`
+}
+
+type createUserResp struct {
+	Org  string `json:"org" xml:"org"`
+	Name string `json:"name" xml:"name"`
+}
+
+func createUser(ctx context.Context, req *createUserReq) (*createUserResp, error) {
+	if req.Name == "" {
+		return nil, NewHTTPError(http.StatusUnprocessableEntity, "name is required")
+	}
+	return &createUserResp{Org: req.Org, Name: req.Name}, nil
+}
+
+func TestTyped(t *testing.T) {
+	mux := NewServeMux()
+	mux.Post("/orgs/{org}/users", Typed(createUser, TypedStatus(http.StatusCreated)))
+
+	req := httptest.NewRequest(http.MethodPost, "/orgs/acme/users", strings.NewReader(`{"name":"bob"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, MIMEApplicationJSON, rec.Header().Get(HeaderContentType))
+	assert.JSONEq(t, `{"org":"acme","name":"bob"}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/orgs/acme/users", strings.NewReader(`{"name":"bob"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	req.Header.Set(HeaderAccept, "application/xml, application/json;q=0.5")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, MIMEApplicationXMLCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "<name>bob</name>")
+
+	req = httptest.NewRequest(http.MethodPost, "/orgs/acme/users", strings.NewReader(`{}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{"msg":"name is required"}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/orgs/acme/users", strings.NewReader(`{`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTyped_NilResponse(t *testing.T) {
+	mux := NewServeMux()
+	mux.Delete("/users/{id}", Typed(func(ctx context.Context, req *struct{}) (*struct{}, error) {
+		return nil, nil
+	}, TypedStatus(http.StatusNoContent)))
+
+	req := httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestHandleTyped(t *testing.T) {
+	mux := NewServeMux()
+	var hooked RouteInfo
+	mux.OnRoute(func(route RouteInfo) { hooked = route })
+	api := mux.Group("/api/")
+	route := HandleTyped(api, http.MethodPost, "/orgs/{org}/users", createUser, TypedStatus(http.StatusCreated))
+
+	info := route.Info()
+	assert.Equal(t, info, hooked)
+	assert.Equal(t, "POST /api/orgs/{org}/users", info.Pattern)
+	assert.Equal(t, reflect.TypeFor[createUserReq](), info.Request)
+	assert.Equal(t, reflect.TypeFor[createUserResp](), info.Response)
+	assert.Equal(t, http.StatusCreated, info.Status)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/orgs/acme/users", strings.NewReader(`{"name":"bob"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"org":"acme","name":"bob"}`, rec.Body.String())
+}
+
+func TestTyped_Error(t *testing.T) {
+	boom := errors.New("boom")
+	h := Typed(func(ctx context.Context, req *struct{}) (*struct{}, error) {
+		return nil, boom
+	})
+
+	err := h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, boom, err)
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", MIMEApplicationJSON},
+		{"*/*", MIMEApplicationJSON},
+		{"application/xml", MIMEApplicationXML},
+		{"application/*;q=0.5, application/xml", MIMEApplicationXML},
+		{"application/xml;q=0.2, application/json;q=0.8", MIMEApplicationJSON},
+		{"text/html", MIMEApplicationJSON},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderAccept, tt.accept)
+		assert.Equal(t, tt.want, negotiate(req, MIMEApplicationJSON, MIMEApplicationXML), tt.accept)
+	}
+}
@@ -0,0 +1,128 @@
+package httpz
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// RouteError describes a problem found while registering a route or a group.
+type RouteError struct {
+	Pattern string // Pattern, prefix, host, version or mapped error being registered
+	File    string // File of the registration
+	Line    int    // Line of the registration
+	Err     error
+}
+
+// Error returns the location of the registration followed by the problem.
+func (e *RouteError) Error() string {
+	return fmt.Sprintf("%s:%d: %q: %v", e.File, e.Line, e.Pattern, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// CollectErrors makes the mux and its groups collect registration problems,
+// such as conflicting patterns, invalid group prefixes, unknown versions or
+// invalid error mappings, instead of panicking.
+// The problems are reported by Validate. Call it before registering routes.
+// A group whose prefix lacks the trailing slash is created as if it had one,
+// so the registrations after it are checked too.
+//
+//	mux := httpz.NewServeMux()
+//	mux.CollectErrors()
+//	// register routes
+//	if err := mux.Validate(); err != nil {
+//		log.Fatal(err)
+//	}
+func (sm *ServeMux) CollectErrors() {
+	sm.registry().collect.Store(true)
+}
+
+// Validate returns the registration problems collected since CollectErrors,
+// joined together, each one a *RouteError. It returns nil when there is none.
+// A route that failed to register is not served and not listed by Routes.
+func (sm *ServeMux) Validate() error {
+	reg := sm.registry()
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return errors.Join(reg.errs...)
+}
+
+// fail panics with v, or records it with the location of the registration
+// when the registry collects errors.
+func (rr *routeRegistry) fail(pattern string, v any) {
+	if !rr.collect.Load() {
+		panic(v)
+	}
+
+	err, ok := v.(error)
+	if !ok {
+		err = errors.New(fmt.Sprint(v))
+	}
+	file, line := registrationSite()
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.errs = append(rr.errs, &RouteError{Pattern: pattern, File: file, Line: line, Err: err})
+}
+
+// register registers h on the net/http mux and reports whether it succeeded.
+// net/http panics on invalid and conflicting patterns, the panic is recorded
+// when the registry collects errors.
+func (sm *ServeMux) register(pattern string, h http.Handler) (ok bool) {
+	reg := sm.registry()
+	if reg.collect.Load() {
+		defer func() {
+			if v := recover(); v != nil {
+				reg.fail(sm.routePattern(pattern), v)
+				ok = false
+			}
+		}()
+	}
+
+	full := sm.fullPattern(pattern)
+	if wildcard, ok := isCatchAll(full); ok {
+		owner := sm.rootMux()
+		if sm.hostGroup != nil {
+			owner = sm.hostGroup
+		}
+		if !owner.setCatchAll(full, wildcard, h) {
+			reg.fail(sm.routePattern(pattern), fmt.Sprintf("pattern %q conflicts with pattern %q", full, owner.catchAll.Load().pattern))
+			return false
+		}
+		return true
+	}
+
+	sm.routeMux().Handle(full, h)
+	return true
+}
+
+// detached returns a Route that is not recorded in the registry, for a route that failed to register.
+func detached(info RouteInfo) *Route {
+	reg := &routeRegistry{routes: []RouteInfo{info}}
+	return &Route{reg: reg, idx: 0}
+}
+
+var pkgPrefix = reflect.TypeFor[ServeMux]().PkgPath() + "."
+
+// registrationSite returns the file and line of the first caller outside of
+// the runtime, net/http and this package, the tests of this package excepted.
+func registrationSite() (string, int) {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		internal := strings.HasPrefix(frame.Function, "runtime.") ||
+			strings.HasPrefix(frame.Function, "net/http.") ||
+			strings.HasPrefix(frame.Function, pkgPrefix) && !strings.HasSuffix(frame.File, "_test.go")
+		if !internal || !more {
+			return frame.File, frame.Line
+		}
+	}
+}
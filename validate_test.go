@@ -0,0 +1,111 @@
+package httpz
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMux_Validate(t *testing.T) {
+	mux := NewServeMux()
+	mux.CollectErrors()
+
+	mux.Get("/users/{id}", listUsers)
+	assert.NoError(t, mux.Validate())
+
+	mux.Get("/users/{name}", listUsers) // conflict
+	api := mux.Group("/api")            // missing trailing slash
+	api.Get("/users", listUsers)
+	mux.Method("BAD METHOD", "/x", listUsers)
+	mux.Get("/orders", listUsers).Name("users")
+	mux.Get("/carts", listUsers).Name("users")
+	route := mux.Get("/users/{slug}", listUsers).Name("conflict") // conflict, not recorded
+
+	err := mux.Validate()
+	assert.Error(t, err)
+
+	var routeErrs []*RouteError
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var re *RouteError
+		if assert.True(t, errors.As(e, &re)) {
+			routeErrs = append(routeErrs, re)
+		}
+	}
+
+	if assert.Len(t, routeErrs, 5) {
+		assert.Equal(t, "GET /users/{name}", routeErrs[0].Pattern)
+		assert.Contains(t, routeErrs[0].Err.Error(), "conflicts with pattern")
+		assert.Equal(t, "/api", routeErrs[1].Pattern)
+		assert.Equal(t, "BAD METHOD /x", routeErrs[2].Pattern)
+		assert.Equal(t, "GET /carts", routeErrs[3].Pattern)
+		assert.Equal(t, "GET /users/{slug}", routeErrs[4].Pattern)
+
+		for _, re := range routeErrs {
+			assert.Equal(t, "validate_test.go", filepath.Base(re.File))
+			assert.NotZero(t, re.Line)
+		}
+		assert.Contains(t, err.Error(), "validate_test.go:")
+	}
+
+	// the group is usable and the failed routes are not served or listed
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, "conflict", route.Info().Name)
+	for _, info := range mux.Routes() {
+		assert.NotEqual(t, "GET /users/{name}", info.Pattern)
+		assert.NotEqual(t, "GET /users/{slug}", info.Pattern)
+	}
+}
+
+func TestServeMux_ValidatePanicsByDefault(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/users/{id}", listUsers)
+
+	assert.Panics(t, func() { mux.Get("/users/{name}", listUsers) })
+	assert.PanicsWithValue(t, "the last char in the prefix must be /", func() { mux.Group("/api") })
+	assert.NoError(t, mux.Validate())
+}
+
+func TestServeMux_ValidateVersionsAndErrorMaps(t *testing.T) {
+	mux := NewServeMux()
+	mux.CollectErrors()
+
+	versions := mux.Versions(VersionConfig{Header: "X-API-Version"})
+	versions.Version("v1/beta").Get("/users", listUsers)
+	versions.Deprecate("v9", time.Now())
+	mux.MapError(nil, http.StatusNotFound)
+	mux.MapError(sql.ErrNoRows, 42)
+	MapErrorAs[*notOwnerError](mux, nil)
+
+	var routeErrs []*RouteError
+	for _, e := range mux.Validate().(interface{ Unwrap() []error }).Unwrap() {
+		var re *RouteError
+		if assert.True(t, errors.As(e, &re)) {
+			routeErrs = append(routeErrs, re)
+		}
+	}
+
+	if assert.Len(t, routeErrs, 5) {
+		assert.Equal(t, "httpz: invalid version v1/beta", routeErrs[0].Err.Error())
+		assert.Equal(t, "httpz: unknown version v9", routeErrs[1].Err.Error())
+		assert.Equal(t, "httpz: nil target error", routeErrs[2].Err.Error())
+		assert.Equal(t, "httpz: invalid status code 42", routeErrs[3].Err.Error())
+		assert.Equal(t, "httpz: nil error mapping function", routeErrs[4].Err.Error())
+
+		for _, re := range routeErrs {
+			assert.Equal(t, "validate_test.go", filepath.Base(re.File))
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/beta/users", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
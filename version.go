@@ -0,0 +1,194 @@
+package httpz
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VersionConfig configures how Versions reads the API version of a request.
+type VersionConfig struct {
+	// Header names a request header carrying the version, such as X-API-Version. Optional.
+	Header string
+	// Vendor enables versions in the Accept header, such as
+	// application/vnd.<Vendor>.v2+json. Optional.
+	Vendor string
+	// Default is the version used when the request names none. Optional.
+	Default string
+}
+
+// Versions serves several versions of an API side by side.
+// Each version is a group named after the version, so the routes of v2 are
+// registered under /v2/. A request may name the version
+//
+//   - in the path, /v2/users,
+//   - in the header named by VersionConfig.Header, X-API-Version: v2,
+//   - in the Accept header, application/vnd.<Vendor>.v2+json,
+//
+// in that order of precedence, otherwise the default version is used.
+// A request naming the version outside the path is routed as if the version
+// was in its path, so r.URL.Path and r.Pattern include the version, and its
+// response varies on the version headers. A request naming an unknown version,
+// or matching a route outside the versions that the version does not have, is
+// routed unchanged.
+type Versions struct {
+	mux *ServeMux
+	cfg VersionConfig
+
+	mu       sync.RWMutex
+	versions map[string]*ServeMux
+}
+
+// Versions returns the API versions grouped under the mux.
+//
+//	versions := mux.Versions(httpz.VersionConfig{Header: "X-API-Version", Default: "v2"})
+//	v1 := versions.Version("v1")
+//	v2 := versions.Version("v2")
+//	versions.Deprecate("v1", sunsetAt)
+func (sm *ServeMux) Versions(cfg VersionConfig) *Versions {
+	v := &Versions{mux: sm, cfg: cfg, versions: make(map[string]*ServeMux)}
+	sm.registry().addVersions(v)
+	return v
+}
+
+// Version returns the group serving the version, creating it on the first call.
+// It panics when the version is empty or contains a slash or a brace, unless
+// errors are collected, then the routes of the returned mux are not served.
+func (v *Versions) Version(version string) *ServeMux {
+	if version == "" || strings.ContainsAny(version, "/{}") {
+		v.mux.registry().fail(version, "httpz: invalid version "+version)
+		return NewServeMux()
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	g, ok := v.versions[version]
+	if !ok {
+		g = v.mux.Group("/" + version + "/")
+		v.versions[version] = g
+	}
+	return g
+}
+
+// Deprecate marks a version as retired. Its responses carry the Sunset and
+// Deprecation headers with the sunset date, and a Link header for each link,
+// like middleware.Sunset. It panics when the version does not exist, unless
+// errors are collected.
+func (v *Versions) Deprecate(version string, sunsetAt time.Time, links ...string) {
+	v.mu.RLock()
+	g, ok := v.versions[version]
+	v.mu.RUnlock()
+	if !ok {
+		v.mux.registry().fail(version, "httpz: unknown version "+version)
+		return
+	}
+
+	g.Use(sunset(sunsetAt, links))
+}
+
+func (v *Versions) has(version string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.versions[version]
+	return ok
+}
+
+// requested returns the version named by the headers of the request, or the default version.
+func (v *Versions) requested(r *http.Request) string {
+	if v.cfg.Header != "" {
+		if version := r.Header.Get(v.cfg.Header); version != "" {
+			return version
+		}
+	}
+
+	if v.cfg.Vendor != "" {
+		prefix := "application/vnd." + strings.ToLower(v.cfg.Vendor) + "."
+		for _, part := range strings.Split(r.Header.Get(HeaderAccept), ",") {
+			mediaType, _, _ := strings.Cut(part, ";")
+			mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+			if version, ok := strings.CutPrefix(mediaType, prefix); ok {
+				version, _, _ = strings.Cut(version, "+")
+				return version
+			}
+		}
+	}
+
+	return v.cfg.Default
+}
+
+// resolveVersion returns the request with the version inserted in its path when
+// it names the version of a Versions of scope outside the path. The path is
+// left unchanged when the request matches a route of mux that the versioned
+// path does not, so unversioned routes next to the versions keep being served.
+// The Vary header lists the request headers the version was read from.
+func (sm *ServeMux) resolveVersion(w http.ResponseWriter, r *http.Request, scope *ServeMux) *http.Request {
+	for _, v := range sm.registry().listVersions() {
+		if v.mux.hostGroup != scope.hostGroup {
+			continue
+		}
+
+		n, ok := prefixLen(v.mux.prefix, r.URL.Path)
+		if !ok {
+			continue
+		}
+		if segment, _ := cutSegment(r.URL.Path[n+1:]); v.has(segment) {
+			return r
+		}
+
+		version := v.requested(r)
+		if version == "" || !v.has(version) {
+			continue
+		}
+
+		u := *r.URL
+		u.Path = u.Path[:n] + "/" + version + u.Path[n:]
+		if m, ok := prefixLen(v.mux.prefix, u.RawPath); ok {
+			u.RawPath = u.RawPath[:m] + "/" + version + u.RawPath[m:]
+		} else {
+			u.RawPath = ""
+		}
+
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = &u
+
+		if _, pattern := scope.lookup(r2); pattern == "" {
+			if _, pattern := scope.lookup(r); pattern != "" {
+				return r
+			}
+		}
+
+		v.vary(w)
+		return r2
+	}
+	return r
+}
+
+// vary adds the request headers naming the version to the Vary header.
+func (v *Versions) vary(w http.ResponseWriter) {
+	if v.cfg.Header != "" {
+		w.Header().Add(HeaderVary, v.cfg.Header)
+	}
+	if v.cfg.Vendor != "" {
+		w.Header().Add(HeaderVary, HeaderAccept)
+	}
+}
+
+// sunset sets the Sunset and Deprecation headers like middleware.Sunset.
+func sunset(sunsetAt time.Time, links []string) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !sunsetAt.IsZero() {
+				w.Header().Set("Sunset", sunsetAt.UTC().Format(http.TimeFormat))
+				w.Header().Set("Deprecation", sunsetAt.UTC().Format(http.TimeFormat))
+
+				for _, link := range links {
+					w.Header().Add("Link", link)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
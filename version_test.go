@@ -0,0 +1,119 @@
+package httpz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersions(t *testing.T) {
+	mux := NewServeMux()
+	api := mux.Group("/api/")
+	versions := api.Versions(VersionConfig{Header: "X-API-Version", Vendor: "acme", Default: "v2"})
+
+	for _, version := range []string{"v1", "v2"} {
+		versions.Version(version).Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+			return String(w, http.StatusOK, version+" "+r.PathValue("id")+" "+r.Pattern)
+		})
+	}
+	sunsetAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions.Deprecate("v1", sunsetAt, `<https://example.com/migrate>; rel="sunset"`)
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		accept string
+		code   int
+		body   string
+	}{
+		{"path", "/api/v1/users/1", "", "", http.StatusOK, "v1 1 GET /api/v1/users/{id}"},
+		{"path wins", "/api/v1/users/1", "v2", "", http.StatusOK, "v1 1 GET /api/v1/users/{id}"},
+		{"header", "/api/users/1", "v1", "", http.StatusOK, "v1 1 GET /api/v1/users/{id}"},
+		{"accept", "/api/users/1", "", "application/vnd.acme.v1+json", http.StatusOK, "v1 1 GET /api/v1/users/{id}"},
+		{"header wins", "/api/users/1", "v2", "application/vnd.acme.v1+json", http.StatusOK, "v2 1 GET /api/v2/users/{id}"},
+		{"default", "/api/users/1", "", "application/json", http.StatusOK, "v2 1 GET /api/v2/users/{id}"},
+		{"unknown", "/api/users/1", "v9", "", http.StatusNotFound, "404 page not found\n"},
+		{"outside", "/users/1", "v1", "", http.StatusNotFound, "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("X-API-Version", tt.header)
+			}
+			if tt.accept != "" {
+				req.Header.Set(HeaderAccept, tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/1", nil)
+	req.Header.Set("X-API-Version", "v1")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, "Tue, 01 Jan 2030 00:00:00 GMT", rec.Header().Get("Sunset"))
+	assert.Equal(t, "Tue, 01 Jan 2030 00:00:00 GMT", rec.Header().Get("Deprecation"))
+	assert.Equal(t, `<https://example.com/migrate>; rel="sunset"`, rec.Header().Get("Link"))
+	assert.Equal(t, []string{"X-API-Version", "Accept"}, rec.Header().Values(HeaderVary))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users/1", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Sunset"))
+}
+
+func TestVersions_Unversioned(t *testing.T) {
+	mux := NewServeMux()
+	mux.Get("/healthz", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, "ok")
+	})
+	mux.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, "unversioned")
+	})
+	versions := mux.Versions(VersionConfig{Header: "X-API-Version", Default: "v2"})
+	versions.Version("v2").Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return String(w, http.StatusOK, "v2")
+	})
+
+	tests := []struct {
+		path string
+		code int
+		body string
+		vary string
+	}{
+		{"/healthz", http.StatusOK, "ok", ""},
+		{"/users/1", http.StatusOK, "v2", "X-API-Version"},
+		{"/nothing", http.StatusNotFound, "404 page not found\n", "X-API-Version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+			assert.Equal(t, tt.vary, rec.Header().Get(HeaderVary))
+		})
+	}
+}
+
+func TestVersions_Panics(t *testing.T) {
+	versions := NewServeMux().Versions(VersionConfig{})
+	assert.Panics(t, func() { versions.Version("") })
+	assert.Panics(t, func() { versions.Version("v1/beta") })
+	assert.Panics(t, func() { versions.Deprecate("v1", time.Now()) })
+	assert.Same(t, versions.Version("v1"), versions.Version("v1"))
+}